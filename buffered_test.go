@@ -0,0 +1,42 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func BenchmarkBufferedWrites(b *testing.B) {
+	for _, bench := range []struct {
+		name     string
+		buffered bool
+	}{
+		{name: "sync", buffered: false},
+		{name: "buffered", buffered: true},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			file, err := os.Create(filepath.Join(b.TempDir(), "bench.log"))
+			if err != nil {
+				b.Fatalf("unexpected error: %v", err)
+			}
+
+			l, err := NewLogger(Configuration{Output: file, BufferedWrites: bench.buffered})
+			if err != nil {
+				b.Fatalf("unexpected error: %v", err)
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				l.Infow("benchmark", "iteration", i)
+			}
+
+			b.StopTimer()
+
+			if err := l.Close(); err != nil {
+				b.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package log
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// badKey is used as the key for values without a matching key.
+const badKey = "!BADKEY"
+
+const (
+	// argErrorKey is used as the key for errors in the arguments of a
+	// log statement.
+	argErrorKey = "log_arg_error"
+
+	oddKeyValuePairsError = "odd number of key-value pairs"
+)
+
+// A CheckedLogEntry is a log statement that will be logged once it is
+// written. It is obtained via Check.
+type CheckedLogEntry struct {
+	entry  *zapcore.CheckedEntry
+	logger *Logger
+}
+
+// Check returns a CheckedLogEntry, if a log statement with the given
+// level and message would be logged, and nil otherwise. This allows
+// skipping the creation of expensive fields for disabled levels, e.g.
+//
+//	if ce := logger.Check(log.DebugLevel, "cache state"); ce != nil {
+//		ce.Write("entries", cache.Dump())
+//	}
+func (l *Logger) Check(level Level, msg string) *CheckedLogEntry {
+	l = handleUninitialized(l)
+
+	ce := l.logger.Desugar().Check(zapcore.Level(level), msg)
+	if ce == nil {
+		return nil
+	}
+
+	return &CheckedLogEntry{
+		entry:  ce,
+		logger: l,
+	}
+}
+
+// Write logs the checked log statement with the given fields. A
+// CheckedLogEntry must only be written once.
+func (e *CheckedLogEntry) Write(keyValuePairs ...any) {
+	if e == nil {
+		return
+	}
+
+	e.entry.Write(toFields(e.logger.resolveFields(keyValuePairs))...)
+}
+
+// WriteFields logs the checked log statement with the given typed
+// fields. In contrast to Write, it does not allocate for the fields, as
+// long as no PII fields created via PIIField are passed and neither key
+// filters, reserved keys nor value truncation are configured. A
+// CheckedLogEntry must only be written once.
+func (e *CheckedLogEntry) WriteFields(fields ...Field) {
+	if e == nil {
+		return
+	}
+
+	e.logger.writeCheckedFields(e.entry, fields)
+}
+
+// toFields converts key-value pairs into fields. Values without a
+// matching string key are added under badKey.
+func toFields(keyValuePairs []any) []zap.Field {
+	fields := make([]zap.Field, 0, len(keyValuePairs)/2+1)
+
+	for i := 0; i < len(keyValuePairs); i++ {
+		if f, ok := keyValuePairs[i].(zap.Field); ok {
+			fields = append(fields, f)
+
+			continue
+		}
+
+		key, ok := keyValuePairs[i].(string)
+		if !ok || i == len(keyValuePairs)-1 {
+			fields = append(fields, zap.Any(badKey, keyValuePairs[i]))
+
+			continue
+		}
+
+		fields = append(fields, zap.Any(key, keyValuePairs[i+1]))
+		i++
+	}
+
+	return fields
+}
@@ -0,0 +1,45 @@
+package log
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestCheckedLogEntryWriteFieldsResolvesPII(t *testing.T) {
+	l, buf := newTestLogger(t, Configuration{PIIMode: PIIModeHash})
+
+	ce := l.Check(InfoLevel, "checked")
+	if ce == nil {
+		t.Fatal("expected a checked entry for an enabled level")
+	}
+
+	ce.WriteFields(zap.Int("n", 1), PIIField("email", "jane@example.com"))
+
+	lines := buf.lines(t)
+	if len(lines) != 1 {
+		t.Fatalf("expected 1 log line, got %d: %s", len(lines), buf.String())
+	}
+
+	if got, want := lines[0]["email"], hash("jane@example.com"); got != want {
+		t.Errorf("expected hashed email %q, got %v", want, got)
+	}
+
+	if got := lines[0]["n"]; got != float64(1) {
+		t.Errorf("expected n to be 1, got %v", got)
+	}
+}
+
+func TestCheckDisabledLevelDoesNotAllocate(t *testing.T) {
+	l, _ := newTestLogger(t, Configuration{MinimumLogLevel: InfoLevel})
+
+	allocs := testing.AllocsPerRun(100, func() {
+		if ce := l.Check(DebugLevel, "disabled"); ce != nil {
+			ce.WriteFields(zap.Int("n", 1))
+		}
+	})
+
+	if allocs != 0 {
+		t.Errorf("expected no allocations, got %v", allocs)
+	}
+}
@@ -0,0 +1,100 @@
+package log
+
+import "context"
+
+type contextKey struct{}
+
+type levelContextKey struct{}
+
+type applicationContextKey struct{}
+
+// The applicationInfo holds the application name and version of a
+// logger, which are passed to its ContextExtractors via the context.
+type applicationInfo struct {
+	name    string
+	version string
+}
+
+// applicationFromContext returns the application name and version of the
+// logger, which calls the ContextExtractor with the context.
+func applicationFromContext(ctx context.Context) applicationInfo {
+	info, _ := ctx.Value(applicationContextKey{}).(applicationInfo)
+
+	return info
+}
+
+// ContextWithLogger returns a copy of the context holding the given
+// logger, e.g. to carry a request-scoped logger created via With
+// through a call chain.
+func ContextWithLogger(ctx context.Context, l ILogger) context.Context {
+	return context.WithValue(ctx, contextKey{}, l)
+}
+
+// FromContext returns the logger stored in the context by
+// ContextWithLogger. If the context does not hold a logger, the
+// package-level logger is returned.
+func FromContext(ctx context.Context) ILogger {
+	if ctx != nil {
+		if l, ok := ctx.Value(contextKey{}).(ILogger); ok && l != nil {
+			return l
+		}
+	}
+
+	return packageLogger()
+}
+
+// ContextWithLevel returns a copy of the context requesting the given
+// minimum log level for loggers derived via WithContext, e.g. to debug a
+// single request without changing the level of the whole application.
+func ContextWithLevel(ctx context.Context, level Level) context.Context {
+	return context.WithValue(ctx, levelContextKey{}, level)
+}
+
+// LevelFromContext returns the minimum log level requested by the
+// context via ContextWithLevel, if any.
+func LevelFromContext(ctx context.Context) (Level, bool) {
+	if ctx == nil {
+		return InfoLevel, false
+	}
+
+	level, ok := ctx.Value(levelContextKey{}).(Level)
+
+	return level, ok
+}
+
+// A ContextExtractor extracts key-value pairs from a context, e.g. trace
+// IDs, which are added to loggers via WithContext.
+type ContextExtractor func(ctx context.Context) []any
+
+// WithContext returns a new logger containing the fields extracted from
+// the context by the ContextExtractors of the logger. If the context
+// requests a log level via ContextWithLevel, the new logger uses it like
+// WithLevel.
+func (l *Logger) WithContext(ctx context.Context) ILogger {
+	l = handleUninitialized(l)
+
+	if ctx == nil {
+		return l
+	}
+
+	if level, ok := LevelFromContext(ctx); ok {
+		l = l.withLevel(level)
+	}
+
+	if len(l.contextExtractors) == 0 {
+		return l
+	}
+
+	extractCtx := context.WithValue(ctx, applicationContextKey{}, l.application)
+
+	keyValuePairs := make([]any, 0)
+	for _, extract := range l.contextExtractors {
+		keyValuePairs = append(keyValuePairs, extract(extractCtx)...)
+	}
+
+	if len(keyValuePairs) == 0 {
+		return l
+	}
+
+	return l.withFields(toFields(l.resolveFields(keyValuePairs))...)
+}
@@ -0,0 +1,52 @@
+package log
+
+import (
+	"context"
+	"strconv"
+)
+
+// DatadogSpanFunc returns the 64-bit trace and span ID of the Datadog
+// span held by the context. If the context does not hold a span, ok
+// shall be false.
+type DatadogSpanFunc func(ctx context.Context) (traceID, spanID uint64, ok bool)
+
+// NewDatadogContextExtractor creates a ContextExtractor, which adds the
+// fields Datadog uses to correlate logs and traces. The service and
+// version are taken from the ApplicationName and Version of the logger,
+// unless they are overridden by non-empty values. As this package does
+// not depend on the Datadog tracer, the IDs are obtained via spanFunc,
+// e.g. using tracer.SpanFromContext.
+func NewDatadogContextExtractor(service, version string, spanFunc DatadogSpanFunc) ContextExtractor {
+	return func(ctx context.Context) []any {
+		traceID, spanID, ok := spanFunc(ctx)
+		if !ok {
+			return nil
+		}
+
+		service, version := service, version
+		application := applicationFromContext(ctx)
+
+		if service == "" {
+			service = application.name
+		}
+
+		if version == "" {
+			version = application.version
+		}
+
+		keyValuePairs := []any{
+			"dd.trace_id", strconv.FormatUint(traceID, 10),
+			"dd.span_id", strconv.FormatUint(spanID, 10),
+		}
+
+		if service != "" {
+			keyValuePairs = append(keyValuePairs, "dd.service", service)
+		}
+
+		if version != "" {
+			keyValuePairs = append(keyValuePairs, "dd.version", version)
+		}
+
+		return keyValuePairs
+	}
+}
@@ -0,0 +1,61 @@
+package log
+
+import (
+	"context"
+	"testing"
+)
+
+func TestDatadogContextExtractor(t *testing.T) {
+	spanFunc := func(ctx context.Context) (uint64, uint64, bool) {
+		return 1, 2, true
+	}
+
+	tests := []struct {
+		name        string
+		service     string
+		version     string
+		wantService string
+		wantVersion string
+	}{
+		{
+			name:        "from configuration",
+			wantService: "shop",
+			wantVersion: "1.2.3",
+		},
+		{
+			name:        "overridden",
+			service:     "shop-worker",
+			version:     "1.2.4",
+			wantService: "shop-worker",
+			wantVersion: "1.2.4",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, buf := newTestLogger(t, Configuration{
+				ApplicationName:   "shop",
+				Version:           "1.2.3",
+				ContextExtractors: []ContextExtractor{NewDatadogContextExtractor(tt.service, tt.version, spanFunc)},
+			})
+
+			l.WithContext(context.Background()).Infow("traced")
+
+			lines := buf.lines(t)
+			if len(lines) != 1 {
+				t.Fatalf("expected 1 log line, got %d: %s", len(lines), buf.String())
+			}
+
+			for key, want := range map[string]string{
+				"dd.trace_id": "1",
+				"dd.span_id":  "2",
+				"dd.service":  tt.wantService,
+				"dd.version":  tt.wantVersion,
+			} {
+				if got := lines[0][key]; got != want {
+					t.Errorf("expected %s to be %q, got %v", key, want, got)
+				}
+			}
+		})
+	}
+}
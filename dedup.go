@@ -0,0 +1,216 @@
+package log
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap/zapcore"
+)
+
+// DeduplicationConfiguration configures the suppression of identical
+// log statements. If a log statement with the same level and message is
+// logged more than Threshold times within Window, the extra statements
+// are suppressed. Once the window has closed, a single summary statement
+// with the number of suppressed statements is logged.
+type DeduplicationConfiguration struct {
+	// Window sets the time window in which identical log statements
+	// are counted. It must be greater than 0.
+	Window time.Duration
+
+	// Threshold sets how many identical log statements are logged per
+	// window before suppression starts. It must be greater than 0.
+	Threshold int
+}
+
+func validateDeduplicationConf(conf DeduplicationConfiguration) error {
+	if conf.Window <= 0 {
+		return errors.New("deduplication window must be greater than 0")
+	}
+
+	if conf.Threshold <= 0 {
+		return errors.New("deduplication threshold must be greater than 0")
+	}
+
+	return nil
+}
+
+type dedupKey struct {
+	level   zapcore.Level
+	message string
+}
+
+type dedupWindow struct {
+	key        dedupKey
+	start      time.Time
+	count      int
+	suppressed int
+	closed     bool
+
+	// The last suppressed entry and the core it was logged on are kept
+	// to write the summary with the same context.
+	lastEntry zapcore.Entry
+	lastCore  zapcore.Core
+}
+
+type dedupState struct {
+	conf      DeduplicationConfiguration
+	mu        sync.Mutex
+	windows   map[dedupKey]*dedupWindow
+	lastSweep time.Time
+}
+
+// The dedupCore suppresses identical log statements. The state is shared
+// with all cores derived from it via With. The summary of a window is
+// written by a timer, once the window has closed, or earlier, if the
+// window is closed by a later log statement or Sync.
+type dedupCore struct {
+	zapcore.Core
+	state *dedupState
+}
+
+func newDedupCore(core zapcore.Core, conf DeduplicationConfiguration) zapcore.Core {
+	return &dedupCore{
+		Core: core,
+		state: &dedupState{
+			conf:    conf,
+			windows: make(map[dedupKey]*dedupWindow),
+		},
+	}
+}
+
+func (c *dedupCore) With(fields []zapcore.Field) zapcore.Core {
+	return &dedupCore{
+		Core:  c.Core.With(fields),
+		state: c.state,
+	}
+}
+
+func (c *dedupCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.Core.Enabled(ent.Level) {
+		return ce
+	}
+
+	allowed, closed := c.state.track(ent, c.Core)
+	writeDedupSummaries(closed)
+
+	if !allowed {
+		return ce
+	}
+
+	return c.Core.Check(ent, ce)
+}
+
+func (c *dedupCore) Sync() error {
+	writeDedupSummaries(c.state.flush())
+
+	return c.Core.Sync()
+}
+
+// track counts the entry in its window and reports whether it shall be
+// logged. Additionally, all windows that have been closed in the
+// meantime and suppressed statements are returned.
+func (s *dedupState) track(ent zapcore.Entry, core zapcore.Core) (bool, []*dedupWindow) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var closed []*dedupWindow
+
+	if ent.Time.Sub(s.lastSweep) >= s.conf.Window {
+		s.lastSweep = ent.Time
+
+		for _, w := range s.windows {
+			if ent.Time.Sub(w.start) >= s.conf.Window {
+				closed = s.close(w, closed)
+			}
+		}
+	}
+
+	key := dedupKey{level: ent.Level, message: ent.Message}
+	w, ok := s.windows[key]
+
+	if !ok || ent.Time.Sub(w.start) >= s.conf.Window {
+		if ok {
+			closed = s.close(w, closed)
+		}
+
+		s.windows[key] = &dedupWindow{key: key, start: ent.Time, count: 1}
+
+		return true, closed
+	}
+
+	w.count++
+	if w.count <= s.conf.Threshold {
+		return true, closed
+	}
+
+	if w.suppressed == 0 {
+		time.AfterFunc(s.conf.Window-ent.Time.Sub(w.start), func() {
+			s.expire(w)
+		})
+	}
+
+	w.suppressed++
+	w.lastEntry = ent
+	w.lastCore = core
+
+	return false, closed
+}
+
+// close removes the window and appends it to the closed windows, if it
+// has suppressed statements and its summary has not been written yet.
+// The state has to be locked by the caller.
+func (s *dedupState) close(w *dedupWindow, closed []*dedupWindow) []*dedupWindow {
+	if s.windows[w.key] == w {
+		delete(s.windows, w.key)
+	}
+
+	if w.closed || w.suppressed == 0 {
+		return closed
+	}
+
+	w.closed = true
+
+	return append(closed, w)
+}
+
+// expire closes the window once its time has passed and writes its
+// summary, unless it has been closed in the meantime.
+func (s *dedupState) expire(w *dedupWindow) {
+	s.mu.Lock()
+	closed := s.close(w, nil)
+	s.mu.Unlock()
+
+	writeDedupSummaries(closed)
+}
+
+// flush closes all windows and returns the ones with suppressed
+// statements.
+func (s *dedupState) flush() []*dedupWindow {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var closed []*dedupWindow
+
+	for _, w := range s.windows {
+		closed = s.close(w, closed)
+	}
+
+	return closed
+}
+
+func writeDedupSummaries(windows []*dedupWindow) {
+	for _, w := range windows {
+		ent := w.lastEntry
+		ent.Time = time.Now()
+		ent.Message = fmt.Sprintf("%s (suppressed %d duplicate messages)", ent.Message, w.suppressed)
+		ent.Stack = ""
+
+		// The summary is checked against the core, so that it is only
+		// written to the sinks accepting its level.
+		if ce := w.lastCore.Check(ent, nil); ce != nil {
+			ce.Write()
+		}
+	}
+}
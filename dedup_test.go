@@ -0,0 +1,56 @@
+package log
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDeduplicationWritesSummaryWhenWindowCloses(t *testing.T) {
+	l, buf := newTestLogger(t, Configuration{
+		Deduplication: &DeduplicationConfiguration{Window: 50 * time.Millisecond, Threshold: 1},
+	})
+
+	for i := 0; i < 5; i++ {
+		l.Info("duplicate")
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for !strings.Contains(buf.String(), "suppressed") && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	lines := buf.lines(t)
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 log lines, got %d: %s", len(lines), buf.String())
+	}
+
+	if got, want := lines[1]["message"], "duplicate (suppressed 4 duplicate messages)"; got != want {
+		t.Errorf("expected summary %q, got %q", want, got)
+	}
+}
+
+func TestDeduplicationWritesSummaryOnSync(t *testing.T) {
+	l, buf := newTestLogger(t, Configuration{
+		Deduplication: &DeduplicationConfiguration{Window: time.Hour, Threshold: 2},
+	})
+
+	for i := 0; i < 3; i++ {
+		l.Warn("duplicate")
+	}
+
+	_ = l.Sync()
+
+	lines := buf.lines(t)
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 log lines, got %d: %s", len(lines), buf.String())
+	}
+
+	if got, want := lines[2]["message"], "duplicate (suppressed 1 duplicate messages)"; got != want {
+		t.Errorf("expected summary %q, got %q", want, got)
+	}
+
+	if got := lines[2]["severity"]; got != "warn" {
+		t.Errorf("expected summary on the warn level, got %v", got)
+	}
+}
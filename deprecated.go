@@ -0,0 +1,36 @@
+package log
+
+import (
+	"runtime"
+	"sync"
+)
+
+// deprecatedCallSites holds the program counters of the call sites,
+// which have already logged a deprecation warning.
+var deprecatedCallSites sync.Map
+
+// DeprecatedOnce logs a warning about the usage of the deprecated
+// feature, but only the first time it is called from a call site, so
+// that migrations can be tracked without flooding the logs.
+func (l *Logger) DeprecatedOnce(feature string) {
+	l = handleUninitialized(l)
+
+	if !firstCallFromCallSite(2) {
+		return
+	}
+
+	l.logger.Warnw("deprecated feature used", "feature", feature)
+}
+
+// firstCallFromCallSite reports whether the call site skip frames above
+// the caller has not been seen before.
+func firstCallFromCallSite(skip int) bool {
+	pc, _, _, ok := runtime.Caller(skip)
+	if !ok {
+		return true
+	}
+
+	_, seen := deprecatedCallSites.LoadOrStore(pc, struct{}{})
+
+	return !seen
+}
@@ -0,0 +1,111 @@
+package log
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	envApplicationName = "LOG_APP"
+	envVersion         = "LOG_VERSION"
+	envMinimumLogLevel = "LOG_LEVEL"
+	envPIIMode         = "LOG_PII_MODE"
+	envFormat          = "LOG_ENCODER"
+
+	// The aliases follow common twelve-factor naming. They are only used,
+	// if the variables above are not set.
+	envApplicationNameAlias = "APP_NAME"
+	envVersionAlias         = "APP_VERSION"
+	envFormatAlias          = "LOG_FORMAT"
+)
+
+// NewLoggerFromEnv creates a new logger based on the following
+// environment variables:
+//
+//   - LOG_APP or APP_NAME: the application name
+//   - LOG_VERSION or APP_VERSION: the application version
+//   - LOG_LEVEL: the minimum log level, e.g. "info"
+//   - LOG_PII_MODE: the PII mode, e.g. "hash"
+//   - LOG_ENCODER or LOG_FORMAT: the log format, e.g. "json"
+//
+// If both names of a setting are set, the first one takes precedence.
+// Unset variables fall back to the defaults of Configuration. If any
+// variable holds an unknown value, an error will be issued.
+func NewLoggerFromEnv() (*Logger, error) {
+	conf, err := ConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewLogger(conf)
+}
+
+// ConfigFromEnv reads the configuration from the same environment
+// variables as NewLoggerFromEnv and validates it. This allows adjusting
+// the configuration further before creating the logger.
+func ConfigFromEnv() (Configuration, error) {
+	conf, err := configurationFromEnv()
+	if err != nil {
+		return Configuration{}, errors.Wrap(err, "received an error while reading the logger configuration from the environment")
+	}
+
+	if err := validateLoggerConf(conf); err != nil {
+		return Configuration{}, errors.Wrap(err, "received an error while validating the logger configuration")
+	}
+
+	return conf, nil
+}
+
+func configurationFromEnv() (Configuration, error) {
+	conf := Configuration{}
+
+	if _, v, ok := lookupEnv(envApplicationName, envApplicationNameAlias); ok {
+		conf.ApplicationName = v
+	}
+
+	if _, v, ok := lookupEnv(envVersion, envVersionAlias); ok {
+		conf.Version = v
+	}
+
+	if v, ok := os.LookupEnv(envMinimumLogLevel); ok {
+		lvl, err := ParseLevel(v)
+		if err != nil {
+			return Configuration{}, errors.Wrapf(err, "invalid value for %s", envMinimumLogLevel)
+		}
+
+		conf.MinimumLogLevel = lvl
+	}
+
+	if v, ok := os.LookupEnv(envPIIMode); ok {
+		mode, err := ParsePIIMode(v)
+		if err != nil {
+			return Configuration{}, errors.Wrapf(err, "invalid value for %s", envPIIMode)
+		}
+
+		conf.PIIMode = mode
+	}
+
+	if name, v, ok := lookupEnv(envFormat, envFormatAlias); ok {
+		format, err := ParseFormat(v)
+		if err != nil {
+			return Configuration{}, errors.Wrapf(err, "invalid value for %s", name)
+		}
+
+		conf.Format = format
+	}
+
+	return conf, nil
+}
+
+// lookupEnv returns the name and value of the first of the environment
+// variables, which is set.
+func lookupEnv(names ...string) (string, string, bool) {
+	for _, name := range names {
+		if v, ok := os.LookupEnv(name); ok {
+			return name, v, true
+		}
+	}
+
+	return "", "", false
+}
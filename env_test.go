@@ -0,0 +1,42 @@
+package log
+
+import "testing"
+
+func TestConfigFromEnvAliases(t *testing.T) {
+	t.Setenv("APP_NAME", "alias-app")
+	t.Setenv("APP_VERSION", "2.0.0")
+	t.Setenv("LOG_FORMAT", "logfmt")
+
+	conf, err := ConfigFromEnv()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if conf.ApplicationName != "alias-app" || conf.Version != "2.0.0" || conf.Format != FormatLogfmt {
+		t.Errorf("unexpected configuration from aliases: %+v", conf)
+	}
+}
+
+func TestConfigFromEnvPrefersPrimaryNames(t *testing.T) {
+	t.Setenv("LOG_APP", "primary-app")
+	t.Setenv("APP_NAME", "alias-app")
+	t.Setenv("LOG_ENCODER", "gelf")
+	t.Setenv("LOG_FORMAT", "logfmt")
+
+	conf, err := ConfigFromEnv()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if conf.ApplicationName != "primary-app" || conf.Format != FormatGELF {
+		t.Errorf("expected the primary names to take precedence: %+v", conf)
+	}
+}
+
+func TestConfigFromEnvInvalidAliasValue(t *testing.T) {
+	t.Setenv("LOG_FORMAT", "xml")
+
+	if _, err := ConfigFromEnv(); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
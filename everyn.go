@@ -0,0 +1,46 @@
+package log
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// everyNCounters holds the number of occurrences per key of EveryN.
+var everyNCounters sync.Map
+
+// EveryN reports whether a log statement shall be logged for the
+// current occurrence of the key, which is true for the 1st, (n+1)th,
+// (2n+1)th, etc. occurrence. This allows limiting repeated logs at a
+// call site, e.g.
+//
+//	if logger.EveryN("retry", 100) {
+//		logger.Warnw("retrying request", "attempt", attempt)
+//	}
+//
+// The occurrences are counted across all loggers, so keys shall be
+// unique per call site. If n is smaller than 2, EveryN always returns
+// true.
+func (l *Logger) EveryN(key string, n int) bool {
+	return everyN(key, n)
+}
+
+// EveryN reports whether a log statement shall be logged for the
+// current occurrence of the key like Logger.EveryN.
+func EveryN(key string, n int) bool {
+	return everyN(key, n)
+}
+
+func everyN(key string, n int) bool {
+	if n < 2 {
+		return true
+	}
+
+	counter, ok := everyNCounters.Load(key)
+	if !ok {
+		counter, _ = everyNCounters.LoadOrStore(key, new(uint64))
+	}
+
+	occurrence := atomic.AddUint64(counter.(*uint64), 1)
+
+	return (occurrence-1)%uint64(n) == 0
+}
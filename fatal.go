@@ -0,0 +1,25 @@
+package log
+
+import (
+	"os"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// The fatalFlushHook flushes all sinks of a logger, including buffered
+// ones, before exiting on fatal log statements. Otherwise, the log
+// statement describing why the application is exiting might get lost.
+type fatalFlushHook struct {
+	core       zapcore.Core
+	closeFuncs []func() error
+}
+
+func (h *fatalFlushHook) OnWrite(_ *zapcore.CheckedEntry, _ []zapcore.Field) {
+	_ = h.core.Sync()
+
+	for _, closeFunc := range h.closeFuncs {
+		_ = closeFunc()
+	}
+
+	os.Exit(1)
+}
@@ -0,0 +1,44 @@
+package log
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// TimeField creates a field holding a point in time, which is encoded
+// in the same way as the timestamp of the log statement.
+func TimeField(key string, t time.Time) zap.Field {
+	return zap.Time(key, t)
+}
+
+// DurationField creates a field holding a duration, which is encoded
+// according to the duration encoding of the logger, i.e. in
+// milliseconds.
+func DurationField(key string, d time.Duration) zap.Field {
+	return zap.Duration(key, d)
+}
+
+// Object creates a field holding a value, which defines its own
+// structured representation by implementing zapcore.ObjectMarshaler.
+// The value is encoded as a nested object.
+func Object(key string, m zapcore.ObjectMarshaler) zap.Field {
+	return zap.Object(key, m)
+}
+
+// Array creates a field holding a value, which defines its own
+// structured representation by implementing zapcore.ArrayMarshaler.
+// The value is encoded as a nested array.
+func Array(key string, m zapcore.ArrayMarshaler) zap.Field {
+	return zap.Array(key, m)
+}
+
+// Stringer creates a field holding a value, which is rendered via its
+// String method. Values implementing fmt.Stringer, which are passed as
+// plain key-value pairs, are rendered the same way, unless they also
+// implement error or zapcore.ObjectMarshaler.
+func Stringer(key string, v fmt.Stringer) zap.Field {
+	return zap.Stringer(key, v)
+}
@@ -0,0 +1,109 @@
+package log
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+type accountID struct {
+	prefix string
+	number int
+}
+
+func (a accountID) String() string {
+	return a.prefix + "-" + strconv.Itoa(a.number)
+}
+
+func TestStringerFields(t *testing.T) {
+	id := accountID{prefix: "acc", number: 7}
+
+	tests := []struct {
+		name string
+		log  func(l *Logger)
+	}{
+		{
+			name: "direct value",
+			log:  func(l *Logger) { l.Infow("msg", "account", id) },
+		},
+		{
+			name: "helper",
+			log:  func(l *Logger) { l.Infow("msg", Stringer("account", id)) },
+		},
+		{
+			name: "helper via With",
+			log:  func(l *Logger) { l.With(Stringer("account", id)).Infow("msg") },
+		},
+		{
+			name: "helper via typed fields",
+			log:  func(l *Logger) { l.InfoFields("msg", Stringer("account", id)) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, buf := newTestLogger(t, Configuration{})
+
+			tt.log(l)
+
+			lines := buf.lines(t)
+			if len(lines) != 1 {
+				t.Fatalf("expected 1 log line, got %d: %s", len(lines), buf.String())
+			}
+
+			if got := lines[0]["account"]; got != "acc-7" {
+				t.Errorf("expected account to be rendered via String, got %v", got)
+			}
+		})
+	}
+}
+
+// An order is an example type, which defines its own structured
+// representation.
+type order struct {
+	id    string
+	items []string
+}
+
+func (o order) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("id", o.id)
+	enc.AddInt("count", len(o.items))
+
+	return enc.AddArray("items", orderItems(o.items))
+}
+
+type orderItems []string
+
+func (items orderItems) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, item := range items {
+		enc.AppendString(item)
+	}
+
+	return nil
+}
+
+func TestObjectAndArrayFields(t *testing.T) {
+	o := order{id: "o-1", items: []string{"apple", "pear"}}
+
+	l, buf := newTestLogger(t, Configuration{})
+
+	l.Infow("marshaled", Object("order", o), Array("items", orderItems(o.items)))
+
+	lines := buf.lines(t)
+	if len(lines) != 1 {
+		t.Fatalf("expected 1 log line, got %d: %s", len(lines), buf.String())
+	}
+
+	wantItems := []any{"apple", "pear"}
+	wantOrder := map[string]any{"id": "o-1", "count": float64(2), "items": wantItems}
+
+	if got := lines[0]["order"]; !reflect.DeepEqual(got, wantOrder) {
+		t.Errorf("expected order %v, got %v", wantOrder, got)
+	}
+
+	if got := lines[0]["items"]; !reflect.DeepEqual(got, wantItems) {
+		t.Errorf("expected items %v, got %v", wantItems, got)
+	}
+}
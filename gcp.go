@@ -0,0 +1,48 @@
+package log
+
+import "go.uber.org/zap/zapcore"
+
+var gcpEncoderConfig = zapcore.EncoderConfig{
+	MessageKey:          "message",
+	LevelKey:            "severity",
+	TimeKey:             "time",
+	NameKey:             "logger",
+	CallerKey:           "caller",
+	FunctionKey:         "func",
+	StacktraceKey:       "stacktrace",
+	SkipLineEnding:      false,
+	LineEnding:          "\n",
+	EncodeLevel:         gcpLevelEncoder,
+	EncodeTime:          zapcore.RFC3339NanoTimeEncoder,
+	EncodeDuration:      zapcore.MillisDurationEncoder,
+	EncodeCaller:        zapcore.ShortCallerEncoder,
+	EncodeName:          nil,
+	NewReflectedEncoder: nil,
+}
+
+// gcpSeverity maps a zap level to the LogSeverity names used by
+// Google Cloud Logging.
+func gcpSeverity(lvl zapcore.Level) string {
+	switch lvl {
+	case zapcore.DebugLevel:
+		return "DEBUG"
+	case zapcore.InfoLevel:
+		return "INFO"
+	case zapcore.WarnLevel:
+		return "WARNING"
+	case zapcore.ErrorLevel:
+		return "ERROR"
+	case zapcore.DPanicLevel:
+		return "CRITICAL"
+	case zapcore.PanicLevel:
+		return "ALERT"
+	case zapcore.FatalLevel:
+		return "EMERGENCY"
+	default:
+		return "DEFAULT"
+	}
+}
+
+func gcpLevelEncoder(lvl zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+	enc.AppendString(gcpSeverity(lvl))
+}
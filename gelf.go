@@ -0,0 +1,289 @@
+package log
+
+import (
+	"crypto/rand"
+	"net"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	gelfVersion = "1.1"
+
+	// gelfChunkSize is the maximum payload size of a single chunked
+	// GELF UDP datagram. It is chosen to safely fit into common MTUs.
+	gelfChunkSize = 1420
+
+	// gelfMaxChunks is the maximum number of chunks a GELF message can
+	// be split into.
+	gelfMaxChunks = 128
+
+	gelfChunkHeaderSize = 12
+)
+
+var gelfChunkMagicBytes = []byte{0x1e, 0x0f}
+
+var gelfEncoderConfig = zapcore.EncoderConfig{
+	MessageKey:          "short_message",
+	LevelKey:            "level",
+	TimeKey:             "timestamp",
+	NameKey:             "_name",
+	CallerKey:           "_caller",
+	FunctionKey:         "_func",
+	StacktraceKey:       "_stacktrace",
+	SkipLineEnding:      false,
+	LineEnding:          "\n",
+	EncodeLevel:         gelfLevelEncoder,
+	EncodeTime:          zapcore.EpochTimeEncoder,
+	EncodeDuration:      zapcore.MillisDurationEncoder,
+	EncodeCaller:        zapcore.ShortCallerEncoder,
+	EncodeName:          nil,
+	NewReflectedEncoder: nil,
+}
+
+// syslogSeverity maps a zap level to the numeric syslog severity as
+// defined in RFC 5424.
+func syslogSeverity(lvl zapcore.Level) int64 {
+	switch lvl {
+	case zapcore.DebugLevel:
+		return 7
+	case zapcore.InfoLevel:
+		return 6
+	case zapcore.WarnLevel:
+		return 4
+	case zapcore.ErrorLevel:
+		return 3
+	default:
+		return 2
+	}
+}
+
+func gelfLevelEncoder(lvl zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+	enc.AppendInt64(syslogSeverity(lvl))
+}
+
+// The gelfEncoder wraps a JSON encoder and prefixes all additional
+// fields with an underscore as demanded by the GELF specification.
+type gelfEncoder struct {
+	zapcore.Encoder
+	host string
+}
+
+func newGELFEncoder(conf zapcore.EncoderConfig) (zapcore.Encoder, error) {
+	host, err := os.Hostname()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not determine the host name for GELF logs")
+	}
+
+	return &gelfEncoder{
+		Encoder: zapcore.NewJSONEncoder(conf),
+		host:    host,
+	}, nil
+}
+
+func (e *gelfEncoder) Clone() zapcore.Encoder {
+	return &gelfEncoder{
+		Encoder: e.Encoder.Clone(),
+		host:    e.host,
+	}
+}
+
+func (e *gelfEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	enc := e.Encoder.Clone()
+	enc.AddString("version", gelfVersion)
+	enc.AddString("host", e.host)
+
+	prefixed := make([]zapcore.Field, len(fields))
+	for i, f := range fields {
+		f.Key = gelfKey(f.Key)
+		prefixed[i] = f
+	}
+
+	return enc.EncodeEntry(ent, prefixed)
+}
+
+func (e *gelfEncoder) AddArray(key string, marshaler zapcore.ArrayMarshaler) error {
+	return e.Encoder.AddArray(gelfKey(key), marshaler)
+}
+
+func (e *gelfEncoder) AddObject(key string, marshaler zapcore.ObjectMarshaler) error {
+	return e.Encoder.AddObject(gelfKey(key), marshaler)
+}
+
+func (e *gelfEncoder) AddBinary(key string, value []byte) {
+	e.Encoder.AddBinary(gelfKey(key), value)
+}
+
+func (e *gelfEncoder) AddByteString(key string, value []byte) {
+	e.Encoder.AddByteString(gelfKey(key), value)
+}
+
+func (e *gelfEncoder) AddBool(key string, value bool) {
+	e.Encoder.AddBool(gelfKey(key), value)
+}
+
+func (e *gelfEncoder) AddComplex128(key string, value complex128) {
+	e.Encoder.AddComplex128(gelfKey(key), value)
+}
+
+func (e *gelfEncoder) AddComplex64(key string, value complex64) {
+	e.Encoder.AddComplex64(gelfKey(key), value)
+}
+
+func (e *gelfEncoder) AddDuration(key string, value time.Duration) {
+	e.Encoder.AddDuration(gelfKey(key), value)
+}
+
+func (e *gelfEncoder) AddFloat64(key string, value float64) {
+	e.Encoder.AddFloat64(gelfKey(key), value)
+}
+
+func (e *gelfEncoder) AddFloat32(key string, value float32) {
+	e.Encoder.AddFloat32(gelfKey(key), value)
+}
+
+func (e *gelfEncoder) AddInt(key string, value int) {
+	e.Encoder.AddInt(gelfKey(key), value)
+}
+
+func (e *gelfEncoder) AddInt64(key string, value int64) {
+	e.Encoder.AddInt64(gelfKey(key), value)
+}
+
+func (e *gelfEncoder) AddInt32(key string, value int32) {
+	e.Encoder.AddInt32(gelfKey(key), value)
+}
+
+func (e *gelfEncoder) AddInt16(key string, value int16) {
+	e.Encoder.AddInt16(gelfKey(key), value)
+}
+
+func (e *gelfEncoder) AddInt8(key string, value int8) {
+	e.Encoder.AddInt8(gelfKey(key), value)
+}
+
+func (e *gelfEncoder) AddString(key, value string) {
+	e.Encoder.AddString(gelfKey(key), value)
+}
+
+func (e *gelfEncoder) AddTime(key string, value time.Time) {
+	e.Encoder.AddTime(gelfKey(key), value)
+}
+
+func (e *gelfEncoder) AddUint(key string, value uint) {
+	e.Encoder.AddUint(gelfKey(key), value)
+}
+
+func (e *gelfEncoder) AddUint64(key string, value uint64) {
+	e.Encoder.AddUint64(gelfKey(key), value)
+}
+
+func (e *gelfEncoder) AddUint32(key string, value uint32) {
+	e.Encoder.AddUint32(gelfKey(key), value)
+}
+
+func (e *gelfEncoder) AddUint16(key string, value uint16) {
+	e.Encoder.AddUint16(gelfKey(key), value)
+}
+
+func (e *gelfEncoder) AddUint8(key string, value uint8) {
+	e.Encoder.AddUint8(gelfKey(key), value)
+}
+
+func (e *gelfEncoder) AddUintptr(key string, value uintptr) {
+	e.Encoder.AddUintptr(gelfKey(key), value)
+}
+
+func (e *gelfEncoder) AddReflected(key string, value interface{}) error {
+	return e.Encoder.AddReflected(gelfKey(key), value)
+}
+
+func (e *gelfEncoder) OpenNamespace(key string) {
+	e.Encoder.OpenNamespace(gelfKey(key))
+}
+
+func gelfKey(key string) string {
+	if key == "" {
+		return key
+	}
+
+	return "_" + key
+}
+
+// The GELFUDPWriter writes GELF messages to a Graylog UDP input. Messages
+// exceeding the size of a single datagram are split into GELF chunks.
+type GELFUDPWriter struct {
+	conn net.Conn
+}
+
+// NewGELFUDPWriter creates a new writer sending GELF messages via UDP to
+// the given address, e.g. "graylog.example.com:12201". It is meant to be
+// used as the Output of a logger using FormatGELF.
+func NewGELFUDPWriter(addr string) (*GELFUDPWriter, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not connect to the GELF UDP address")
+	}
+
+	return &GELFUDPWriter{conn: conn}, nil
+}
+
+// Write sends a single encoded log statement as one GELF message.
+func (w *GELFUDPWriter) Write(p []byte) (int, error) {
+	msg := p
+	if len(msg) > 0 && msg[len(msg)-1] == '\n' {
+		msg = msg[:len(msg)-1]
+	}
+
+	if len(msg) <= gelfChunkSize {
+		if _, err := w.conn.Write(msg); err != nil {
+			return 0, errors.Wrap(err, "could not write GELF message")
+		}
+
+		return len(p), nil
+	}
+
+	chunkCount := (len(msg) + gelfChunkSize - 1) / gelfChunkSize
+	if chunkCount > gelfMaxChunks {
+		return 0, errors.New("GELF message exceeds the maximum number of chunks")
+	}
+
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return 0, errors.Wrap(err, "could not generate GELF message ID")
+	}
+
+	chunk := make([]byte, 0, gelfChunkHeaderSize+gelfChunkSize)
+
+	for i := 0; i < chunkCount; i++ {
+		end := (i + 1) * gelfChunkSize
+		if end > len(msg) {
+			end = len(msg)
+		}
+
+		chunk = append(chunk[:0], gelfChunkMagicBytes...)
+		chunk = append(chunk, id...)
+		chunk = append(chunk, byte(i), byte(chunkCount))
+		chunk = append(chunk, msg[i*gelfChunkSize:end]...)
+
+		if _, err := w.conn.Write(chunk); err != nil {
+			return 0, errors.Wrap(err, "could not write GELF message chunk")
+		}
+	}
+
+	return len(p), nil
+}
+
+// Sync is a no-op, as UDP messages are not buffered.
+func (w *GELFUDPWriter) Sync() error {
+	return nil
+}
+
+// Close closes the underlying UDP connection.
+func (w *GELFUDPWriter) Close() error {
+	return w.conn.Close()
+}
@@ -0,0 +1,53 @@
+package log
+
+import (
+	"compress/gzip"
+	"sync"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// The gzipWriteSyncer compresses all logs written to the wrapped
+// WriteSyncer. Sync flushes the compressed data written so far, while
+// Close terminates the gzip stream.
+type gzipWriteSyncer struct {
+	mu sync.Mutex
+	gz *gzip.Writer
+	ws zapcore.WriteSyncer
+}
+
+func newGzipWriteSyncer(ws zapcore.WriteSyncer) *gzipWriteSyncer {
+	return &gzipWriteSyncer{
+		gz: gzip.NewWriter(ws),
+		ws: ws,
+	}
+}
+
+func (w *gzipWriteSyncer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.gz.Write(p)
+}
+
+func (w *gzipWriteSyncer) Sync() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.gz.Flush(); err != nil {
+		return err
+	}
+
+	return w.ws.Sync()
+}
+
+func (w *gzipWriteSyncer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.gz.Close(); err != nil {
+		return err
+	}
+
+	return w.ws.Sync()
+}
@@ -0,0 +1,86 @@
+package log
+
+import (
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// The keyFilter drops fields based on their keys. Keys are compared
+// case-insensitively.
+type keyFilter struct {
+	denied  map[string]struct{}
+	allowed map[string]struct{}
+}
+
+func newKeyFilter(conf Configuration) *keyFilter {
+	if len(conf.DeniedKeys) == 0 && len(conf.AllowedKeys) == 0 {
+		return nil
+	}
+
+	return &keyFilter{
+		denied:  lowerKeySet(conf.DeniedKeys),
+		allowed: lowerKeySet(conf.AllowedKeys),
+	}
+}
+
+func lowerKeySet(keys []string) map[string]struct{} {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	set := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		set[strings.ToLower(key)] = struct{}{}
+	}
+
+	return set
+}
+
+// filter drops all fields from the resolved key-value pairs, whose keys
+// are denied or, if an allowlist is set, not allowed.
+func (f *keyFilter) filter(keyValuePairs []any) []any {
+	out := keyValuePairs[:0]
+
+	for i := 0; i < len(keyValuePairs); i++ {
+		switch element := keyValuePairs[i].(type) {
+		case zap.Field:
+			if element.Type == zapcore.SkipType || f.passes(element.Key) {
+				out = append(out, element)
+			}
+		case string:
+			if i == len(keyValuePairs)-1 {
+				out = append(out, element)
+
+				continue
+			}
+
+			if f.passes(element) {
+				out = append(out, element, keyValuePairs[i+1])
+			}
+
+			i++
+		default:
+			out = append(out, element)
+		}
+	}
+
+	return out
+}
+
+func (f *keyFilter) passes(key string) bool {
+	key = strings.ToLower(key)
+
+	if _, ok := f.denied[key]; ok {
+		return false
+	}
+
+	if f.allowed == nil {
+		return true
+	}
+
+	_, ok := f.allowed[key]
+
+	return ok
+}
@@ -0,0 +1,84 @@
+package log
+
+import "testing"
+
+func TestKeyFilter(t *testing.T) {
+	tests := []struct {
+		name    string
+		conf    Configuration
+		log     func(l *Logger)
+		present []string
+		absent  []string
+	}{
+		{
+			name: "denied keys via w-method",
+			conf: Configuration{DeniedKeys: []string{"ssn", "Password"}},
+			log: func(l *Logger) {
+				l.Infow("msg", "SSN", "123-45-6789", "password", "secret", "user", "jane")
+			},
+			present: []string{"user"},
+			absent:  []string{"SSN", "password"},
+		},
+		{
+			name: "denied keys via With",
+			conf: Configuration{DeniedKeys: []string{"ssn", "Password"}},
+			log: func(l *Logger) {
+				l.With("ssn", "123-45-6789", "PASSWORD", "secret", "user", "jane").Infow("msg")
+			},
+			present: []string{"user"},
+			absent:  []string{"ssn", "PASSWORD"},
+		},
+		{
+			name: "denied PII field",
+			conf: Configuration{DeniedKeys: []string{"email"}, PIIMode: PIIModeHash},
+			log: func(l *Logger) {
+				l.Infow("msg", PII("email", "jane@example.com"), "user", "jane")
+			},
+			present: []string{"user"},
+			absent:  []string{"email"},
+		},
+		{
+			name: "allowed keys via w-method",
+			conf: Configuration{AllowedKeys: []string{"user", "ssn"}, DeniedKeys: []string{"ssn"}},
+			log: func(l *Logger) {
+				l.Infow("msg", "user", "jane", "ssn", "123-45-6789", "other", 1)
+			},
+			present: []string{"user"},
+			absent:  []string{"ssn", "other"},
+		},
+		{
+			name: "allowed keys via With",
+			conf: Configuration{AllowedKeys: []string{"User"}},
+			log: func(l *Logger) {
+				l.With("user", "jane", "other", 1).Infow("msg")
+			},
+			present: []string{"user"},
+			absent:  []string{"other"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, buf := newTestLogger(t, tt.conf)
+
+			tt.log(l)
+
+			lines := buf.lines(t)
+			if len(lines) != 1 {
+				t.Fatalf("expected 1 log line, got %d: %s", len(lines), buf.String())
+			}
+
+			for _, key := range tt.present {
+				if _, ok := lines[0][key]; !ok {
+					t.Errorf("expected key %q to be logged: %s", key, buf.String())
+				}
+			}
+
+			for _, key := range tt.absent {
+				if _, ok := lines[0][key]; ok {
+					t.Errorf("expected key %q to be dropped: %s", key, buf.String())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package log
+
+import (
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// WithLazy returns a new logger containing the added fields. In
+// contrast to With, the fields, including the resolution of PII fields,
+// are only materialized once the new logger actually logs something.
+// This makes it cheap to create many short-lived loggers, which might
+// never be used.
+func (l *Logger) WithLazy(keyValuePairs ...any) ILogger {
+	l = handleUninitialized(l)
+
+	return l.withWrappedCore(func(core zapcore.Core) zapcore.Core {
+		return &lazyWithCore{
+			orig:          core,
+			resolve:       l.resolveFields,
+			keyValuePairs: keyValuePairs,
+		}
+	})
+}
+
+// The lazyWithCore adds its key-value pairs to the wrapped core on the
+// first use only.
+type lazyWithCore struct {
+	orig          zapcore.Core
+	resolve       func(keyValuePairs []any) []any
+	keyValuePairs []any
+
+	once sync.Once
+	core zapcore.Core
+}
+
+func (c *lazyWithCore) initOnce() {
+	c.once.Do(func() {
+		fields := c.resolve(c.keyValuePairs)
+		c.core = zap.New(c.orig).Sugar().With(fields...).Desugar().Core()
+	})
+}
+
+func (c *lazyWithCore) Enabled(lvl zapcore.Level) bool {
+	return c.orig.Enabled(lvl)
+}
+
+func (c *lazyWithCore) With(fields []zapcore.Field) zapcore.Core {
+	c.initOnce()
+
+	return c.core.With(fields)
+}
+
+func (c *lazyWithCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.orig.Enabled(ent.Level) {
+		return ce
+	}
+
+	c.initOnce()
+
+	return c.core.Check(ent, ce)
+}
+
+func (c *lazyWithCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	c.initOnce()
+
+	return c.core.Write(ent, fields)
+}
+
+func (c *lazyWithCore) Sync() error {
+	return c.orig.Sync()
+}
@@ -0,0 +1,48 @@
+package log
+
+import (
+	"io"
+	"testing"
+)
+
+func TestWithLazyResolvesPIIOnUse(t *testing.T) {
+	l, buf := newTestLogger(t, Configuration{PIIMode: PIIModeHash})
+
+	l.WithLazy("request_id", "abc", PII("email", "jane@example.com")).Infow("lazy")
+
+	lines := buf.lines(t)
+	if len(lines) != 1 {
+		t.Fatalf("expected 1 log line, got %d: %s", len(lines), buf.String())
+	}
+
+	if got := lines[0]["request_id"]; got != "abc" {
+		t.Errorf("expected request_id abc, got %v", got)
+	}
+
+	if got, want := lines[0]["email"], hash("jane@example.com"); got != want {
+		t.Errorf("expected hashed email %q, got %v", want, got)
+	}
+}
+
+func BenchmarkChildLoggerWithoutLogging(b *testing.B) {
+	l, err := NewLogger(Configuration{Output: io.Discard, PIIMode: PIIModeHash})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.Run("With", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			_ = l.With("request_id", "abc", "user_id", 42, PII("email", "jane@example.com"))
+		}
+	})
+
+	b.Run("WithLazy", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			_ = l.WithLazy("request_id", "abc", "user_id", 42, PII("email", "jane@example.com"))
+		}
+	})
+}
@@ -0,0 +1,185 @@
+package log
+
+import (
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+var invalidLevelWarning sync.Once
+
+// WithLevel returns a new logger with a different minimum log level,
+// e.g. to make a single subsystem more or less verbose than the rest of
+// the application. The level of a logger can be lowered below the
+// configured MinimumLogLevel, but log statements are still only written
+// to sinks, whose own level range permits them. Loggers derived via
+// WithLazy or Tee can only raise their level.
+func (l *Logger) WithLevel(level Level) ILogger {
+	l = handleUninitialized(l)
+
+	return l.withLevel(level)
+}
+
+// withLevel returns a copy of the logger with the given minimum level.
+func (l *Logger) withLevel(level Level) *Logger {
+	atomicLevel := zap.NewAtomicLevelAt(zapcore.Level(level))
+	out := l.withWrappedCore(func(core zapcore.Core) zapcore.Core {
+		if filter, ok := core.(*levelFilterCore); ok {
+			core = filter.Core
+		}
+
+		return &levelFilterCore{Core: core, level: atomicLevel}
+	})
+	out.level = atomicLevel
+
+	return out
+}
+
+// Level returns the current minimum log level of the logger.
+func (l *Logger) Level() Level {
+	l = handleUninitialized(l)
+
+	return Level(l.level.Level())
+}
+
+// SetLevel changes the minimum log level of the logger at runtime. The
+// change applies to all loggers sharing the level, i.e. the ones derived
+// via With and similar methods, but not the ones derived via WithLevel.
+// It is safe to call SetLevel while the loggers are in use.
+func (l *Logger) SetLevel(level Level) {
+	l = handleUninitialized(l)
+	l.level.SetLevel(zapcore.Level(level))
+}
+
+// WithTemporaryLevel changes the minimum log level of the logger like
+// SetLevel and returns a function, which restores the previous level,
+// e.g. to debug a single block:
+//
+//	defer logger.WithTemporaryLevel(log.DebugLevel)()
+func (l *Logger) WithTemporaryLevel(level Level) (restore func()) {
+	l = handleUninitialized(l)
+
+	previous := l.level.Level()
+	l.level.SetLevel(zapcore.Level(level))
+
+	return func() {
+		l.level.SetLevel(previous)
+	}
+}
+
+// The levelFilterCore enforces the minimum log level of a logger on top
+// of the level ranges of its sinks.
+type levelFilterCore struct {
+	zapcore.Core
+	level zap.AtomicLevel
+}
+
+func (c *levelFilterCore) Enabled(lvl zapcore.Level) bool {
+	return c.level.Enabled(lvl) && c.Core.Enabled(lvl)
+}
+
+func (c *levelFilterCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelFilterCore{Core: c.Core.With(fields), level: c.level}
+}
+
+func (c *levelFilterCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.level.Enabled(ent.Level) {
+		return ce
+	}
+
+	return c.Core.Check(ent, ce)
+}
+
+// Log logs all inputs on the given level, which allows determining the
+// level at runtime. Invalid levels are logged on the info level together
+// with a one-time warning.
+func (l *Logger) Log(level Level, v ...any) {
+	l = handleUninitialized(l)
+
+	switch level {
+	case DebugLevel:
+		l.logger.Debug(v...)
+	case WarnLevel:
+		l.logger.Warn(v...)
+	case ErrorLevel:
+		l.logger.Error(v...)
+	case DPanicLevel:
+		l.logger.DPanic(v...)
+	case PanicLevel:
+		l.logger.Panic(v...)
+	case FatalLevel:
+		l.logger.Fatal(v...)
+	case InfoLevel:
+		l.logger.Info(v...)
+	default:
+		l.warnInvalidLevel(level)
+		l.logger.Info(v...)
+	}
+}
+
+// Logw logs all inputs and fields on the given level, which allows
+// determining the level at runtime. Invalid levels are logged on the
+// info level together with a one-time warning.
+func (l *Logger) Logw(level Level, msg string, keyValuePairs ...any) {
+	l = handleUninitialized(l)
+	fields := l.resolveFields(keyValuePairs)
+
+	switch level {
+	case DebugLevel:
+		l.logger.Debugw(msg, fields...)
+	case WarnLevel:
+		l.logger.Warnw(msg, fields...)
+	case ErrorLevel:
+		l.logger.Errorw(msg, fields...)
+	case DPanicLevel:
+		l.logger.DPanicw(msg, fields...)
+	case PanicLevel:
+		l.logger.Panicw(msg, fields...)
+	case FatalLevel:
+		l.logger.Fatalw(msg, fields...)
+	case InfoLevel:
+		l.logger.Infow(msg, fields...)
+	default:
+		l.warnInvalidLevel(level)
+		l.logger.Infow(msg, fields...)
+	}
+}
+
+// Logf formats and logs all inputs on the given level, which allows
+// determining the level at runtime. Invalid levels are logged on the
+// info level together with a one-time warning.
+func (l *Logger) Logf(level Level, format string, v ...any) {
+	l = handleUninitialized(l)
+
+	switch level {
+	case DebugLevel:
+		l.logger.Debugf(format, v...)
+	case WarnLevel:
+		l.logger.Warnf(format, v...)
+	case ErrorLevel:
+		l.logger.Errorf(format, v...)
+	case DPanicLevel:
+		l.logger.DPanicf(format, v...)
+	case PanicLevel:
+		l.logger.Panicf(format, v...)
+	case FatalLevel:
+		l.logger.Fatalf(format, v...)
+	case InfoLevel:
+		l.logger.Infof(format, v...)
+	default:
+		l.warnInvalidLevel(level)
+		l.logger.Infof(format, v...)
+	}
+}
+
+// warnInvalidLevel warns once about a log statement on an invalid level,
+// which is logged on the info level instead.
+func (l *Logger) warnInvalidLevel(level Level) {
+	invalidLevelWarning.Do(func() {
+		l.logger.Desugar().WithOptions(zap.WithCaller(false)).Warn(
+			"invalid log level - falling back to the info level",
+			zap.Int8("level", int8(level)),
+		)
+	})
+}
@@ -0,0 +1,85 @@
+package log
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestLevelJSONRoundTrip(t *testing.T) {
+	for lvl := range logLevels {
+		data, err := json.Marshal(lvl)
+		if err != nil {
+			t.Fatalf("unexpected error marshaling %v: %v", lvl, err)
+		}
+
+		if want := `"` + lvl.String() + `"`; string(data) != want {
+			t.Errorf("expected %s, got %s", want, data)
+		}
+
+		var got Level
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatalf("unexpected error unmarshaling %s: %v", data, err)
+		}
+
+		if got != lvl {
+			t.Errorf("expected %v after round trip, got %v", lvl, got)
+		}
+	}
+}
+
+func TestPIIModeJSONRoundTrip(t *testing.T) {
+	for mode := range piiModes {
+		data, err := json.Marshal(mode)
+		if err != nil {
+			t.Fatalf("unexpected error marshaling %v: %v", mode, err)
+		}
+
+		if want := `"` + mode.String() + `"`; string(data) != want {
+			t.Errorf("expected %s, got %s", want, data)
+		}
+
+		var got PIIMode
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatalf("unexpected error unmarshaling %s: %v", data, err)
+		}
+
+		if got != mode {
+			t.Errorf("expected %v after round trip, got %v", mode, got)
+		}
+	}
+}
+
+func TestConfigurationFromJSON(t *testing.T) {
+	var conf Configuration
+	if err := json.Unmarshal([]byte(`{"MinimumLogLevel": "WARN", "PIIMode": "hash"}`), &conf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if conf.MinimumLogLevel != WarnLevel {
+		t.Errorf("expected warn level, got %v", conf.MinimumLogLevel)
+	}
+
+	if conf.PIIMode != PIIModeHash {
+		t.Errorf("expected hash PII mode, got %v", conf.PIIMode)
+	}
+}
+
+func TestInvalidJSONValues(t *testing.T) {
+	var lvl Level
+	if err := json.Unmarshal([]byte(`"verbose"`), &lvl); err == nil {
+		t.Error("expected an error for an unknown level")
+	}
+
+	if err := json.Unmarshal([]byte(`1`), &lvl); err == nil {
+		t.Error("expected an error for a numeric level")
+	}
+
+	var mode PIIMode
+	if err := json.Unmarshal([]byte(`"scramble"`), &mode); err == nil {
+		t.Error("expected an error for an unknown PII mode")
+	}
+
+	if _, err := json.Marshal(PIIMode(42)); err == nil {
+		t.Error("expected an error for marshaling an invalid PII mode")
+	}
+}
@@ -0,0 +1,113 @@
+package log
+
+import (
+	stderrors "errors"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// LogErr logs the message and fields together with the error on the
+// error level and returns the error, which allows logging and returning
+// an error in one go, e.g. return logger.LogErr(err, "could not save").
+// If the error is nil, nothing is logged and nil is returned.
+func (l *Logger) LogErr(err error, msg string, keyValuePairs ...any) error {
+	l = handleUninitialized(l)
+
+	if err == nil {
+		return nil
+	}
+
+	fields := l.resolveFields(keyValuePairs)
+	l.logger.Errorw(msg, append(fields, zap.Error(err))...)
+
+	return err
+}
+
+// WithError returns a new logger, which adds the error under the
+// "error" key to all log statements. Errors carrying a stack trace, e.g.
+// from github.com/pkg/errors, additionally add it under the
+// "errorVerbose" key. If the error is nil, the logger is returned as is.
+func (l *Logger) WithError(err error) ILogger {
+	l = handleUninitialized(l)
+
+	if err == nil {
+		return l
+	}
+
+	return l.withFields(zap.Error(err))
+}
+
+// ErrorChain creates a field holding the messages of the error and all
+// errors it wraps, the type of the root cause and, if present, the
+// stack trace of the innermost error carrying one. Errors are unwrapped
+// via errors.Unwrap and the Cause method of github.com/pkg/errors. If
+// the error is nil, the field is skipped.
+func ErrorChain(key string, err error) zap.Field {
+	if err == nil {
+		return zap.Skip()
+	}
+
+	return zap.Object(key, errorChain{err: err})
+}
+
+// The errorChain encodes an error together with all errors it wraps.
+type errorChain struct {
+	err error
+}
+
+func (c errorChain) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	var (
+		messages []string
+		cause    error
+		stack    string
+	)
+
+	for err := c.err; err != nil; err = unwrapError(err) {
+		msg := err.Error()
+		if len(messages) == 0 || messages[len(messages)-1] != msg {
+			messages = append(messages, msg)
+		}
+
+		if tracer, ok := err.(interface{ StackTrace() errors.StackTrace }); ok {
+			stack = strings.TrimSpace(fmt.Sprintf("%+v", tracer.StackTrace()))
+		}
+
+		cause = err
+	}
+
+	if err := enc.AddArray("messages", zapcore.ArrayMarshalerFunc(func(arr zapcore.ArrayEncoder) error {
+		for _, msg := range messages {
+			arr.AppendString(msg)
+		}
+
+		return nil
+	})); err != nil {
+		return err
+	}
+
+	enc.AddString("cause", fmt.Sprintf("%T", cause))
+
+	if stack != "" {
+		enc.AddString("stacktrace", stack)
+	}
+
+	return nil
+}
+
+// unwrapError returns the error wrapped by the given one or nil, if it
+// does not wrap any.
+func unwrapError(err error) error {
+	if wrapped := stderrors.Unwrap(err); wrapped != nil {
+		return wrapped
+	}
+
+	if causer, ok := err.(interface{ Cause() error }); ok {
+		return causer.Cause()
+	}
+
+	return nil
+}
@@ -0,0 +1,402 @@
+package log
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+var logfmtPool = buffer.NewPool()
+
+// The logfmtEncoder encodes log statements as logfmt lines, e.g.
+// ts=2006-01-02T15:04:05Z lvl=info msg="log something" key=value.
+// Arrays, objects and reflected values are encoded as quoted JSON.
+type logfmtEncoder struct {
+	*zapcore.EncoderConfig
+	buf    *buffer.Buffer
+	prefix string
+}
+
+func newLogfmtEncoder(conf zapcore.EncoderConfig) zapcore.Encoder {
+	return &logfmtEncoder{
+		EncoderConfig: &conf,
+		buf:           logfmtPool.Get(),
+	}
+}
+
+func (e *logfmtEncoder) Clone() zapcore.Encoder {
+	return e.clone()
+}
+
+func (e *logfmtEncoder) clone() *logfmtEncoder {
+	buf := logfmtPool.Get()
+	buf.Write(e.buf.Bytes())
+
+	return &logfmtEncoder{
+		EncoderConfig: e.EncoderConfig,
+		buf:           buf,
+		prefix:        e.prefix,
+	}
+}
+
+func (e *logfmtEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	final := &logfmtEncoder{
+		EncoderConfig: e.EncoderConfig,
+		buf:           logfmtPool.Get(),
+	}
+
+	if final.TimeKey != "" {
+		final.AddTime(final.TimeKey, ent.Time)
+	}
+
+	if final.LevelKey != "" && final.EncodeLevel != nil {
+		final.addEncoded(final.LevelKey, ent.Level.String(), func(enc zapcore.PrimitiveArrayEncoder) {
+			final.EncodeLevel(ent.Level, enc)
+		})
+	}
+
+	if ent.LoggerName != "" && final.NameKey != "" {
+		nameEncoder := final.EncodeName
+		if nameEncoder == nil {
+			nameEncoder = zapcore.FullNameEncoder
+		}
+
+		final.addEncoded(final.NameKey, ent.LoggerName, func(enc zapcore.PrimitiveArrayEncoder) {
+			nameEncoder(ent.LoggerName, enc)
+		})
+	}
+
+	if ent.Caller.Defined {
+		if final.CallerKey != "" && final.EncodeCaller != nil {
+			final.addEncoded(final.CallerKey, ent.Caller.String(), func(enc zapcore.PrimitiveArrayEncoder) {
+				final.EncodeCaller(ent.Caller, enc)
+			})
+		}
+
+		if final.FunctionKey != "" {
+			final.AddString(final.FunctionKey, ent.Caller.Function)
+		}
+	}
+
+	if final.MessageKey != "" {
+		final.AddString(final.MessageKey, ent.Message)
+	}
+
+	if e.buf.Len() > 0 {
+		final.addSeparator()
+		final.buf.Write(e.buf.Bytes())
+	}
+
+	final.prefix = e.prefix
+	for _, f := range fields {
+		f.AddTo(final)
+	}
+
+	final.prefix = ""
+	if ent.Stack != "" && final.StacktraceKey != "" {
+		final.AddString(final.StacktraceKey, ent.Stack)
+	}
+
+	if !final.SkipLineEnding {
+		final.buf.AppendString(final.LineEnding)
+	}
+
+	return final.buf, nil
+}
+
+func (e *logfmtEncoder) AddArray(key string, marshaler zapcore.ArrayMarshaler) error {
+	m := zapcore.NewMapObjectEncoder()
+	if err := m.AddArray(key, marshaler); err != nil {
+		return err
+	}
+
+	return e.addJSON(key, m.Fields[key])
+}
+
+func (e *logfmtEncoder) AddObject(key string, marshaler zapcore.ObjectMarshaler) error {
+	m := zapcore.NewMapObjectEncoder()
+	if err := m.AddObject(key, marshaler); err != nil {
+		return err
+	}
+
+	return e.addJSON(key, m.Fields[key])
+}
+
+func (e *logfmtEncoder) AddBinary(key string, value []byte) {
+	e.AddString(key, base64.StdEncoding.EncodeToString(value))
+}
+
+func (e *logfmtEncoder) AddByteString(key string, value []byte) {
+	e.AddString(key, string(value))
+}
+
+func (e *logfmtEncoder) AddBool(key string, value bool) {
+	e.addRaw(key, strconv.FormatBool(value))
+}
+
+func (e *logfmtEncoder) AddComplex128(key string, value complex128) {
+	e.addRaw(key, strconv.FormatComplex(value, 'g', -1, 128))
+}
+
+func (e *logfmtEncoder) AddComplex64(key string, value complex64) {
+	e.addRaw(key, strconv.FormatComplex(complex128(value), 'g', -1, 64))
+}
+
+func (e *logfmtEncoder) AddDuration(key string, value time.Duration) {
+	if e.EncodeDuration == nil {
+		e.AddString(key, value.String())
+
+		return
+	}
+
+	e.addEncoded(key, value.String(), func(enc zapcore.PrimitiveArrayEncoder) {
+		e.EncodeDuration(value, enc)
+	})
+}
+
+func (e *logfmtEncoder) AddFloat64(key string, value float64) {
+	e.addRaw(key, strconv.FormatFloat(value, 'g', -1, 64))
+}
+
+func (e *logfmtEncoder) AddFloat32(key string, value float32) {
+	e.addRaw(key, strconv.FormatFloat(float64(value), 'g', -1, 32))
+}
+
+func (e *logfmtEncoder) AddInt(key string, value int) {
+	e.AddInt64(key, int64(value))
+}
+
+func (e *logfmtEncoder) AddInt64(key string, value int64) {
+	e.addRaw(key, strconv.FormatInt(value, 10))
+}
+
+func (e *logfmtEncoder) AddInt32(key string, value int32) {
+	e.AddInt64(key, int64(value))
+}
+
+func (e *logfmtEncoder) AddInt16(key string, value int16) {
+	e.AddInt64(key, int64(value))
+}
+
+func (e *logfmtEncoder) AddInt8(key string, value int8) {
+	e.AddInt64(key, int64(value))
+}
+
+func (e *logfmtEncoder) AddString(key, value string) {
+	e.addRaw(key, logfmtValue(value))
+}
+
+func (e *logfmtEncoder) AddTime(key string, value time.Time) {
+	if e.EncodeTime == nil {
+		e.AddString(key, value.Format(time.RFC3339))
+
+		return
+	}
+
+	e.addEncoded(key, value.Format(time.RFC3339), func(enc zapcore.PrimitiveArrayEncoder) {
+		e.EncodeTime(value, enc)
+	})
+}
+
+func (e *logfmtEncoder) AddUint(key string, value uint) {
+	e.AddUint64(key, uint64(value))
+}
+
+func (e *logfmtEncoder) AddUint64(key string, value uint64) {
+	e.addRaw(key, strconv.FormatUint(value, 10))
+}
+
+func (e *logfmtEncoder) AddUint32(key string, value uint32) {
+	e.AddUint64(key, uint64(value))
+}
+
+func (e *logfmtEncoder) AddUint16(key string, value uint16) {
+	e.AddUint64(key, uint64(value))
+}
+
+func (e *logfmtEncoder) AddUint8(key string, value uint8) {
+	e.AddUint64(key, uint64(value))
+}
+
+func (e *logfmtEncoder) AddUintptr(key string, value uintptr) {
+	e.AddUint64(key, uint64(value))
+}
+
+func (e *logfmtEncoder) AddReflected(key string, value interface{}) error {
+	if e.NewReflectedEncoder == nil {
+		return e.addJSON(key, value)
+	}
+
+	var b bytes.Buffer
+	if err := e.NewReflectedEncoder(&b).Encode(value); err != nil {
+		return err
+	}
+
+	e.AddString(key, strings.TrimSuffix(b.String(), "\n"))
+
+	return nil
+}
+
+func (e *logfmtEncoder) OpenNamespace(key string) {
+	e.prefix += logfmtKey(key) + "."
+}
+
+func (e *logfmtEncoder) addJSON(key string, value interface{}) error {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	e.AddString(key, string(b))
+
+	return nil
+}
+
+// addEncoded adds the value produced by the given encode function. If
+// the function does not produce any value, the fallback is used.
+func (e *logfmtEncoder) addEncoded(key, fallback string, encode func(enc zapcore.PrimitiveArrayEncoder)) {
+	enc := &logfmtPrimitiveEncoder{}
+	encode(enc)
+
+	if len(enc.values) == 0 {
+		e.AddString(key, fallback)
+
+		return
+	}
+
+	e.AddString(key, strings.Join(enc.values, ","))
+}
+
+func (e *logfmtEncoder) addRaw(key, value string) {
+	e.addSeparator()
+	e.buf.AppendString(e.prefix)
+	e.buf.AppendString(logfmtKey(key))
+	e.buf.AppendByte('=')
+	e.buf.AppendString(value)
+}
+
+func (e *logfmtEncoder) addSeparator() {
+	if e.buf.Len() > 0 {
+		e.buf.AppendByte(' ')
+	}
+}
+
+// logfmtKey replaces all characters that are not allowed in logfmt keys
+// with underscores.
+func logfmtKey(key string) string {
+	if key == "" {
+		return "_"
+	}
+
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r == '=' || r == '"' || r == utf8.RuneError {
+			return '_'
+		}
+
+		return r
+	}, key)
+}
+
+// logfmtValue quotes the value, if it is empty or contains any spaces,
+// quotes, equal signs or control characters.
+func logfmtValue(value string) string {
+	if value == "" {
+		return `""`
+	}
+
+	needsQuoting := strings.IndexFunc(value, func(r rune) bool {
+		return r <= ' ' || r == '=' || r == '"' || r == '\\' || unicode.IsControl(r) || r == utf8.RuneError
+	}) >= 0
+
+	if needsQuoting {
+		return strconv.Quote(value)
+	}
+
+	return value
+}
+
+// The logfmtPrimitiveEncoder collects the values appended by zap's
+// level, time, caller, name and duration encoders as strings.
+type logfmtPrimitiveEncoder struct {
+	values []string
+}
+
+func (e *logfmtPrimitiveEncoder) AppendBool(v bool) {
+	e.values = append(e.values, strconv.FormatBool(v))
+}
+
+func (e *logfmtPrimitiveEncoder) AppendByteString(v []byte) {
+	e.values = append(e.values, string(v))
+}
+
+func (e *logfmtPrimitiveEncoder) AppendComplex128(v complex128) {
+	e.values = append(e.values, strconv.FormatComplex(v, 'g', -1, 128))
+}
+
+func (e *logfmtPrimitiveEncoder) AppendComplex64(v complex64) {
+	e.values = append(e.values, strconv.FormatComplex(complex128(v), 'g', -1, 64))
+}
+
+func (e *logfmtPrimitiveEncoder) AppendFloat64(v float64) {
+	e.values = append(e.values, strconv.FormatFloat(v, 'g', -1, 64))
+}
+
+func (e *logfmtPrimitiveEncoder) AppendFloat32(v float32) {
+	e.values = append(e.values, strconv.FormatFloat(float64(v), 'g', -1, 32))
+}
+
+func (e *logfmtPrimitiveEncoder) AppendInt(v int) {
+	e.AppendInt64(int64(v))
+}
+
+func (e *logfmtPrimitiveEncoder) AppendInt64(v int64) {
+	e.values = append(e.values, strconv.FormatInt(v, 10))
+}
+
+func (e *logfmtPrimitiveEncoder) AppendInt32(v int32) {
+	e.AppendInt64(int64(v))
+}
+
+func (e *logfmtPrimitiveEncoder) AppendInt16(v int16) {
+	e.AppendInt64(int64(v))
+}
+
+func (e *logfmtPrimitiveEncoder) AppendInt8(v int8) {
+	e.AppendInt64(int64(v))
+}
+
+func (e *logfmtPrimitiveEncoder) AppendString(v string) {
+	e.values = append(e.values, v)
+}
+
+func (e *logfmtPrimitiveEncoder) AppendUint(v uint) {
+	e.AppendUint64(uint64(v))
+}
+
+func (e *logfmtPrimitiveEncoder) AppendUint64(v uint64) {
+	e.values = append(e.values, strconv.FormatUint(v, 10))
+}
+
+func (e *logfmtPrimitiveEncoder) AppendUint32(v uint32) {
+	e.AppendUint64(uint64(v))
+}
+
+func (e *logfmtPrimitiveEncoder) AppendUint16(v uint16) {
+	e.AppendUint64(uint64(v))
+}
+
+func (e *logfmtPrimitiveEncoder) AppendUint8(v uint8) {
+	e.AppendUint64(uint64(v))
+}
+
+func (e *logfmtPrimitiveEncoder) AppendUintptr(v uintptr) {
+	e.AppendUint64(uint64(v))
+}
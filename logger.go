@@ -1,9 +1,18 @@
 package log
 
 import (
-	"os"
+	"context"
+	"crypto/cipher"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/pkg/errors"
+	"go.uber.org/multierr"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -13,31 +22,107 @@ import (
 type Level zapcore.Level
 
 const (
-	DebugLevel = Level(zapcore.DebugLevel)
-	InfoLevel  = Level(zapcore.InfoLevel)
-	WarnLevel  = Level(zapcore.WarnLevel)
-	ErrorLevel = Level(zapcore.ErrorLevel)
-	PanicLevel = Level(zapcore.PanicLevel)
-	FatalLevel = Level(zapcore.FatalLevel)
+	DebugLevel  = Level(zapcore.DebugLevel)
+	InfoLevel   = Level(zapcore.InfoLevel)
+	WarnLevel   = Level(zapcore.WarnLevel)
+	ErrorLevel  = Level(zapcore.ErrorLevel)
+	DPanicLevel = Level(zapcore.DPanicLevel)
+	PanicLevel  = Level(zapcore.PanicLevel)
+	FatalLevel  = Level(zapcore.FatalLevel)
 )
 
 var (
 	logLevels = map[Level]struct{}{
-		DebugLevel: {},
-		InfoLevel:  {},
-		WarnLevel:  {},
-		ErrorLevel: {},
-		PanicLevel: {},
-		FatalLevel: {},
+		DebugLevel:  {},
+		InfoLevel:   {},
+		WarnLevel:   {},
+		ErrorLevel:  {},
+		DPanicLevel: {},
+		PanicLevel:  {},
+		FatalLevel:  {},
 	}
 )
 
+// String returns the lowercase name of the level, e.g. "info".
+func (l Level) String() string {
+	return zapcore.Level(l).String()
+}
+
+// ParseLevel parses a level from its name, e.g. "info" or "WARN". An
+// error is returned for unknown level names.
+func ParseLevel(text string) (Level, error) {
+	zapLevel, err := zapcore.ParseLevel(text)
+	if err != nil {
+		return InfoLevel, errors.Errorf("unknown log level %q", text)
+	}
+
+	lvl := Level(zapLevel)
+	if _, ok := logLevels[lvl]; !ok {
+		return InfoLevel, errors.Errorf("unknown log level %q", text)
+	}
+
+	return lvl, nil
+}
+
+// MarshalText encodes the level as its name, e.g. "info", so that
+// levels can be stored in configuration files.
+func (l Level) MarshalText() ([]byte, error) {
+	if _, ok := logLevels[l]; !ok {
+		return nil, errors.Errorf("invalid log level %d", l)
+	}
+
+	return []byte(l.String()), nil
+}
+
+// UnmarshalText decodes the level from its name via ParseLevel.
+func (l *Level) UnmarshalText(text []byte) error {
+	lvl, err := ParseLevel(string(text))
+	if err != nil {
+		return err
+	}
+
+	*l = lvl
+
+	return nil
+}
+
+// MarshalJSON encodes the level as a JSON string holding its name.
+func (l Level) MarshalJSON() ([]byte, error) {
+	text, err := l.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(string(text))
+}
+
+// UnmarshalJSON decodes the level from a JSON string holding its name.
+func (l *Level) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return errors.Wrap(err, "invalid log level")
+	}
+
+	return l.UnmarshalText([]byte(text))
+}
+
+// OutputMode specifies where the logs of a logger will be written.
 type OutputMode uint8
 
 const (
-	OutputStdOut          OutputMode = 0
+	// OutputStdOut writes all logs to stdout using a single core, so
+	// the order of log statements is preserved.
+	OutputStdOut OutputMode = 0
+
+	// OutputStdOutAndStdErr writes logs below the warn level, or the
+	// configured StdErrThreshold, to stdout and all else to stderr. As
+	// these are two separate streams, their log statements may
+	// interleave out of order in log collectors.
 	OutputStdOutAndStdErr OutputMode = 1
-	OutputStdErr          OutputMode = 2
+
+	// OutputStdErr writes all logs to stderr using a single core, so
+	// the order of log statements is preserved.
+	OutputStdErr OutputMode = 2
 )
 
 var (
@@ -48,6 +133,100 @@ var (
 	}
 )
 
+// LevelEncoding specifies how the level of a log statement is encoded.
+type LevelEncoding uint8
+
+const (
+	// LevelEncodingLowercase encodes levels in lowercase, e.g. "info".
+	LevelEncodingLowercase LevelEncoding = 0
+
+	// LevelEncodingUppercase encodes levels in uppercase, e.g. "INFO".
+	LevelEncodingUppercase LevelEncoding = 1
+
+	// LevelEncodingUppercaseColor encodes levels in uppercase and adds
+	// ANSI color codes, e.g. for local development in a terminal.
+	LevelEncodingUppercaseColor LevelEncoding = 2
+
+	// LevelEncodingNumeric encodes levels only as their numeric syslog
+	// severity, e.g. 6 for info, for consumers rejecting textual levels.
+	LevelEncodingNumeric LevelEncoding = 3
+)
+
+var (
+	levelEncodings = map[LevelEncoding]zapcore.LevelEncoder{
+		LevelEncodingLowercase:      zapcore.LowercaseLevelEncoder,
+		LevelEncodingUppercase:      zapcore.CapitalLevelEncoder,
+		LevelEncodingUppercaseColor: zapcore.CapitalColorLevelEncoder,
+		LevelEncodingNumeric:        numericLevelEncoder,
+	}
+)
+
+// Format specifies the format in which log statements are encoded.
+type Format uint8
+
+const (
+	// FormatJSON encodes log statements as plain JSON objects.
+	FormatJSON Format = 0
+
+	// FormatGELF encodes log statements as GELF 1.1 compliant JSON
+	// objects, which can be ingested by Graylog.
+	FormatGELF Format = 1
+
+	// FormatGCP encodes log statements as JSON objects following the
+	// structured logging conventions of Google Cloud Logging.
+	FormatGCP Format = 2
+
+	// FormatLogfmt encodes log statements as logfmt lines, e.g.
+	// timestamp=2006-01-02T15:04:05Z severity=info message="log something".
+	FormatLogfmt Format = 3
+
+	// FormatLogstash encodes log statements as JSON objects following
+	// the conventions of the json codec of Logstash, i.e. with the
+	// "@timestamp" and "@version" fields.
+	FormatLogstash Format = 4
+)
+
+var (
+	formats = map[Format]struct{}{
+		FormatJSON:     {},
+		FormatGELF:     {},
+		FormatGCP:      {},
+		FormatLogfmt:   {},
+		FormatLogstash: {},
+	}
+)
+
+var (
+	formatNames = map[Format]string{
+		FormatJSON:     "json",
+		FormatGELF:     "gelf",
+		FormatGCP:      "gcp",
+		FormatLogfmt:   "logfmt",
+		FormatLogstash: "logstash",
+	}
+)
+
+// String returns the name of the format, e.g. "json".
+func (f Format) String() string {
+	if name, ok := formatNames[f]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("Format(%d)", f)
+}
+
+// ParseFormat parses a format from its name, e.g. "json" or "GELF". An
+// error is returned for unknown format names.
+func ParseFormat(text string) (Format, error) {
+	for format, name := range formatNames {
+		if strings.EqualFold(name, text) {
+			return format, nil
+		}
+	}
+
+	return FormatJSON, errors.Errorf("unknown format %q", text)
+}
+
 var encoderConfig = zapcore.EncoderConfig{
 	MessageKey:          "message",
 	LevelKey:            "severity",
@@ -88,6 +267,12 @@ type Configuration struct {
 	// If the value is set to "", the field will be omitted.
 	Version string
 
+	// SchemaVersion holds the value for the "schema" field in log
+	// statements indicating the version of the log format, so that
+	// consumers can handle changes to it. If the value is set to "",
+	// the field will be omitted.
+	SchemaVersion string
+
 	// MinimumLogLevel sets the minim level of logs that will get
 	// logged by the respective logger. The DebugLevel is the lowest
 	// while the FatalLevel is the highest. If set to Debug, everything
@@ -99,39 +284,279 @@ type Configuration struct {
 	// statements.
 	PIIMode PIIMode
 
+	// PIIEncryptionKey is the AES key used in PIIModeEncrypt, which
+	// must be 16, 24 or 32 bytes long. It is required in this mode and
+	// must be kept secret, as it allows decrypting the logged PII.
+	PIIEncryptionKey []byte
+
 	// OutputMode indicates where the logs will be written. Logs can
 	// either be published to stdout, stderr or split between the two.
 	OutputMode OutputMode
 
+	// StdErrThreshold sets the lowest level, which is written to stderr
+	// in OutputStdOutAndStdErr, e.g. ErrorLevel to keep warnings on
+	// stdout. If not set, the WarnLevel is used.
+	StdErrThreshold *Level
+
+	// Output, if set, receives all logs regardless of the OutputMode.
+	// All levels are written to this single writer, so the order of
+	// log statements is preserved. This can be used to write logs to
+	// files or network destinations, e.g. a GELFUDPWriter.
+	Output io.Writer
+
+	// Format indicates how log statements are encoded. If not set, the
+	// logs are encoded as plain JSON.
+	Format Format
+
+	// Sinks lets you write logs to multiple destinations at once, each
+	// with its own format and level range. If set, OutputMode, Output and
+	// Format are ignored in favor of the sinks. A sink only receives log
+	// statements, which pass both MinimumLogLevel and its own level range.
+	Sinks []Sink
+
+	// Syslog, if set, additionally sends all logs to a syslog daemon
+	// using the configured Format. Syslog is not supported on Windows
+	// and Plan 9, where creating the logger fails with an error.
+	Syslog *SyslogConfiguration
+
+	// RingBuffer, if set, additionally retains the most recent log
+	// statements, which are enabled for the logger, in memory. These
+	// are encoded in the Format of the logger and never buffered.
+	RingBuffer *RingBuffer
+
+	// Sampling, if set, enables the sampling of log statements to cap
+	// the amount of repeated logs.
+	Sampling *SamplingConfiguration
+
+	// SamplingHook, if set, is called for every log statement to decide
+	// whether it is logged or dropped, e.g. to always keep the logs of
+	// specific tenants. Dropped log statements are counted in Stats.
+	SamplingHook SamplingHookFunc
+
+	// Deduplication, if set, enables the suppression of identical log
+	// statements within a time window.
+	Deduplication *DeduplicationConfiguration
+
+	// RateLimitKey, if set, enables rate limiting per distinct value of
+	// the field with this key, e.g. a tenant ID. At most RateLimitCount
+	// log statements per value are logged within RateLimitPeriod, all
+	// further ones are dropped and counted in the Stats of the logger.
+	// Log statements without this field are not limited.
+	RateLimitKey string
+
+	// RateLimitCount sets the maximum number of log statements per value
+	// of the RateLimitKey within RateLimitPeriod.
+	RateLimitCount int
+
+	// RateLimitPeriod sets the period for the RateLimitCount.
+	RateLimitPeriod time.Duration
+
+	// IncludeFunction adds the name of the calling function to log
+	// statements. If not set, the function field is omitted.
+	IncludeFunction bool
+
+	// IncludeSeverityNumber adds the numeric syslog severity of a log
+	// statement as an additional "severity_number" field, while the
+	// textual level is kept as is.
+	IncludeSeverityNumber bool
+
+	// IncludeSequence adds an incrementing "seq" field to every log
+	// statement, which reflects the order in which the log statements
+	// of a logger and the loggers derived from it have been written.
+	IncludeSequence bool
+
 	// KeyNames lets you overwrite the standard key names for common
 	// log fields.
 	KeyNames KeyNames
+
+	// DeniedKeys lists field keys, which are never logged, e.g. "ssn" or
+	// "password", regardless of the PII mode. The keys are matched
+	// case-insensitively against the fields of log statements and With.
+	DeniedKeys []string
+
+	// AllowedKeys lists the only field keys, which are logged, if set.
+	// The keys are matched case-insensitively and DeniedKeys take
+	// precedence.
+	AllowedKeys []string
+
+	// MaxFieldValueBytes limits the length of string values of fields,
+	// e.g. to keep a logged request body from exceeding the size limits
+	// of log ingestion. Longer values are truncated at a character
+	// boundary to fit the limit including a trailing ellipsis and are
+	// followed by a "<key>_truncated" field. If not set, values are not
+	// truncated.
+	MaxFieldValueBytes int
+
+	// OnReservedKeyCollision indicates how fields are handled, whose keys
+	// collide with the keys reserved by the logger, e.g. the message or
+	// the application name key. If not set, collisions are ignored.
+	OnReservedKeyCollision KeyCollisionMode
+
+	// BufferedWrites enables buffering of log writes, so that the
+	// encoded logs are written in batches instead of on every log
+	// statement. Only the periodic flush after the FlushInterval runs in
+	// a background goroutine; a full buffer is flushed synchronously by
+	// the log statement filling it, and the buffer is flushed on Sync
+	// and Close. Be aware that buffered log statements are lost, when
+	// the application crashes hard before the buffer is flushed.
+	BufferedWrites bool
+
+	// BufferSize sets the size of the write buffer in bytes, when
+	// BufferedWrites is enabled. If not set, 256 kB are used.
+	BufferSize int
+
+	// FlushInterval sets how often the write buffer is flushed, when
+	// BufferedWrites is enabled. If not set, 30 seconds are used.
+	FlushInterval time.Duration
+
+	// LevelEncoding indicates how levels are encoded in FormatJSON and
+	// FormatLogfmt. If not set, levels are encoded in lowercase.
+	LevelEncoding LevelEncoding
+
+	// FullCaller logs the full path of the calling file instead of only
+	// the package directory and file name, e.g. to tell apart files with
+	// the same name in different packages.
+	FullCaller bool
+
+	// LineEnding overwrites the line ending, which terminates every log
+	// statement, e.g. "\r\n". If not set, "\n" is used.
+	LineEnding string
+
+	// SkipLineEnding omits the line ending after every log statement,
+	// e.g. for writers, which frame log statements themselves.
+	SkipLineEnding bool
+
+	// NewReflectedEncoder overwrites how field values without a
+	// dedicated field type, e.g. structs and maps, are encoded. If not
+	// set, values are encoded via encoding/json.
+	NewReflectedEncoder func(io.Writer) zapcore.ReflectedEncoder
+
+	// SortFields sorts the fields of every log statement, including the
+	// ones added via With, alphabetically by key, e.g. to compare logs in
+	// golden file tests. This comes at a performance cost.
+	SortFields bool
+
+	// PrettyJSON indents the logs of FormatJSON, so that every log
+	// statement spans multiple lines. This breaks parsers, which expect
+	// one log statement per line, and must therefore only be used for
+	// local debugging. Other formats are not affected.
+	PrettyJSON bool
+
+	// StacktraceLevel, if set, adds a stack trace to all log statements
+	// on this level and above, e.g. ErrorLevel. If not set, no stack
+	// traces are added.
+	StacktraceLevel *Level
+
+	// Development puts the logger into development mode, which makes
+	// DPanic, DPanicf and DPanicw panic after logging. Outside of
+	// development mode, these only log on the dpanic level.
+	Development bool
+
+	// ContextExtractors are used by WithContext to extract fields from a
+	// context, e.g. trace IDs via NewDatadogContextExtractor.
+	ContextExtractors []ContextExtractor
+
+	// Clock sets the source of the timestamps of log statements, e.g. to
+	// produce reproducible output in tests. If not set, the system clock
+	// is used.
+	Clock zapcore.Clock
+
+	// ZapOptions are passed to the underlying zap logger after all
+	// built-in options, so they can override them, e.g. to add hooks or
+	// to wrap the core. Be aware that misusing them can break features
+	// of this package like the PII handling or the output splitting.
+	ZapOptions []zap.Option
+
+	// RePanic indicates whether RecoverAndLog shall re-panic with the
+	// recovered value after it has been logged. RecoverAndRePanic always
+	// re-panics.
+	RePanic bool
 }
 
 type ILogger interface {
 	Debug(v ...any)
 	Debugf(format string, v ...any)
+	Debugln(v ...any)
 	Debugw(msg string, keyValuePairs ...any)
+	DPanic(v ...any)
+	DPanicf(format string, v ...any)
+	DPanicln(v ...any)
+	DPanicw(msg string, keyValuePairs ...any)
 	Error(v ...any)
 	Errorf(format string, v ...any)
+	Errorln(v ...any)
 	Errorw(msg string, keyValuePairs ...any)
 	Fatal(v ...any)
 	Fatalf(format string, v ...any)
+	Fatalln(v ...any)
 	Fatalw(msg string, keyValuePairs ...any)
 	Info(v ...any)
 	Infof(format string, v ...any)
+	Infoln(v ...any)
 	Infow(msg string, keyValuePairs ...any)
+	Log(level Level, v ...any)
+	Logf(level Level, format string, v ...any)
+	Logw(level Level, msg string, keyValuePairs ...any)
+	Panic(v ...any)
+	Panicf(format string, v ...any)
+	Panicln(v ...any)
+	Panicw(msg string, keyValuePairs ...any)
+	RecoverAndLog(msg string)
+	RecoverAndRePanic(msg string)
 	Sync() error
 	Warn(v ...any)
 	Warnf(format string, v ...any)
+	Warnln(v ...any)
 	Warnw(msg string, keyValuePairs ...any)
-	With(keyValuePairs ...any) *Logger
+	With(keyValuePairs ...any) ILogger
+	WithError(err error) ILogger
+	WithFields(fields map[string]any) ILogger
+	WithLazy(keyValuePairs ...any) ILogger
+	Namespace(name string) ILogger
+	WithContext(ctx context.Context) ILogger
+	WithLevel(level Level) ILogger
+	WithPIIMode(mode PIIMode) ILogger
+	Tee(extra zapcore.Core) ILogger
+	Merge(other ILogger) ILogger
+	Without(keys ...string) ILogger
 }
 
+var (
+	_ ILogger   = (*Logger)(nil)
+	_ io.Closer = (*Logger)(nil)
+)
+
+var (
+	// PanicOnUninitialized indicates whether calling methods on a nil
+	// logger shall panic. If not set, the package-level logger is used
+	// instead and a single warning is logged.
+	PanicOnUninitialized bool
+
+	uninitializedWarning sync.Once
+)
+
 // The Logger struct resembles the actual loggers.
 type Logger struct {
-	logger  *zap.SugaredLogger
-	piiMode PIIMode
+	logger     *zap.SugaredLogger
+	base       *zap.Logger
+	root       *zap.SugaredLogger
+	fields     []zap.Field
+	static     []zap.Field
+	piiMode    PIIMode
+	piiCipher  cipher.AEAD
+	level      zap.AtomicLevel
+	rePanic    bool
+	stackKey   string
+	nop        bool
+	stats      *stats
+	closeFuncs []func() error
+
+	contextExtractors []ContextExtractor
+	application       applicationInfo
+	reservedKeys      *reservedKeys
+	keyFilter         *keyFilter
+
+	maxFieldValueBytes int
 }
 
 // NewNOPLogger creates a new no-operation logger that does not write
@@ -139,7 +564,16 @@ type Logger struct {
 // when you need to fulfill the Interface, but you don't want to
 // actually log anything.
 func NewNOPLogger() *Logger {
-	return &Logger{logger: zap.NewNop().Sugar()}
+	sugared := zap.NewNop().Sugar()
+
+	return &Logger{logger: sugared, base: newFieldsBase(sugared), root: sugared, level: zap.NewAtomicLevel(), nop: true}
+}
+
+// IsNop reports whether the logger is a no-operation logger created via
+// NewNOPLogger or derived from one. This allows skipping the creation of
+// expensive diagnostics, which would not be logged anyway.
+func (l *Logger) IsNop() bool {
+	return l != nil && l.nop
 }
 
 // MustNewLogger wraps NewLogger and panics, when an error is encountered.
@@ -152,6 +586,17 @@ func MustNewLogger(c Configuration) *Logger {
 	return l
 }
 
+// NewILogger wraps NewLogger and returns the logger as an ILogger, which
+// makes it easy to swap it for a different implementation, e.g. in tests.
+func NewILogger(c Configuration) (ILogger, error) {
+	l, err := NewLogger(c)
+	if err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
 // NewLogger creates a new logger based on the configuration inputs and
 // returns a pointer to it. If the validation of the input configuration
 // fails an error will be issued.
@@ -161,9 +606,54 @@ func NewLogger(conf Configuration) (*Logger, error) {
 		return nil, errors.Wrap(err, "received an error while validating the logger configuration")
 	}
 
-	core := createCore(conf.OutputMode, conf.MinimumLogLevel, zapcore.WarnLevel)
+	var piiCipher cipher.AEAD
+	if conf.PIIMode == PIIModeEncrypt {
+		piiCipher, err = newPIICipher(conf.PIIEncryptionKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "received an error while creating the PII cipher")
+		}
+	}
+
+	core, closeFuncs, err := createCore(conf)
+	if err != nil {
+		return nil, errors.Wrap(err, "received an error while creating the log core")
+	}
+
+	logStats := &stats{}
+	core = zapcore.RegisterHooks(core, logStats.countLogged)
+
+	if conf.SortFields {
+		core = newSortedFieldsCore(core)
+	}
+
+	// The sequence number is added below sampling, deduplication and rate
+	// limiting, so that it is only assigned to written log statements.
+	if conf.IncludeSequence {
+		core = newSequenceCore(core)
+	}
+
+	if conf.Sampling != nil {
+		core = newSamplingCore(core, *conf.Sampling)
+	}
+
+	if conf.SamplingHook != nil {
+		core = newSamplingHookCore(core, conf.SamplingHook, logStats)
+	}
 
-	fields := make([]zap.Field, 0, 2)
+	if conf.Deduplication != nil {
+		core = newDedupCore(core, *conf.Deduplication)
+	}
+
+	if conf.RateLimitKey != "" {
+		core = newRateLimitCore(core, conf, logStats)
+	}
+
+	// The minimum log level applies to all sinks, which can only raise it
+	// further for themselves.
+	atomicLevel := zap.NewAtomicLevelAt(zapcore.Level(conf.MinimumLogLevel))
+	core = &levelFilterCore{Core: core, level: atomicLevel}
+
+	fields := make([]zap.Field, 0, 3)
 
 	if conf.ApplicationName != "" {
 		fields = append(fields, zap.String("app", conf.ApplicationName))
@@ -173,156 +663,634 @@ func NewLogger(conf Configuration) (*Logger, error) {
 		fields = append(fields, zap.String("version", conf.Version))
 	}
 
-	zapLogger := zap.New(
-		core,
+	if conf.SchemaVersion != "" {
+		fields = append(fields, zap.String("schema", conf.SchemaVersion))
+	}
+
+	opts := []zap.Option{
 		zap.AddCaller(),
 		zap.AddCallerSkip(1),
 		zap.Fields(
 			fields...,
 		),
-	)
+		zap.WithFatalHook(&fatalFlushHook{core: core, closeFuncs: closeFuncs}),
+	}
+
+	if conf.StacktraceLevel != nil {
+		opts = append(opts, zap.AddStacktrace(zapcore.Level(*conf.StacktraceLevel)))
+	}
+
+	if conf.Development {
+		opts = append(opts, zap.Development())
+	}
+
+	if conf.Clock != nil {
+		opts = append(opts, zap.WithClock(conf.Clock))
+	}
+
+	opts = append(opts, conf.ZapOptions...)
+
+	zapLogger := zap.New(core, opts...)
+
+	return &Logger{
+		logger:     zapLogger.Sugar(),
+		base:       newFieldsBase(zapLogger.Sugar()),
+		root:       zapLogger.Sugar(),
+		static:     fields,
+		piiMode:    conf.PIIMode,
+		piiCipher:  piiCipher,
+		level:      atomicLevel,
+		rePanic:    conf.RePanic,
+		stackKey:   stacktraceKey(conf),
+		stats:      logStats,
+		closeFuncs: closeFuncs,
+
+		contextExtractors: conf.ContextExtractors,
+		application:       applicationInfo{name: conf.ApplicationName, version: conf.Version},
+		reservedKeys:      newReservedKeys(conf),
+		keyFilter:         newKeyFilter(conf),
+
+		maxFieldValueBytes: conf.MaxFieldValueBytes,
+	}, nil
+}
+
+// NewLoggerFromCore creates a new logger writing to the given core and
+// returns a pointer to it, e.g. for custom encoders or sinks, which are
+// not covered by the configuration. The logger resolves PII fields
+// according to the PII mode and adds the caller to all log statements.
+// The options are applied after the ones of the logger. As no
+// encryption key can be passed, PIIModeEncrypt is not supported.
+func NewLoggerFromCore(core zapcore.Core, piiMode PIIMode, opts ...zap.Option) (*Logger, error) {
+	if core == nil {
+		return nil, errors.New("invalid nil core")
+	}
+
+	if _, ok := piiModes[piiMode]; !ok || piiMode == PIIModeEncrypt {
+		return nil, errors.New("invalid PII mode")
+	}
+
+	logStats := &stats{}
+	atomicLevel := zap.NewAtomicLevelAt(zapcore.DebugLevel)
+	core = &levelFilterCore{Core: zapcore.RegisterHooks(core, logStats.countLogged), level: atomicLevel}
+
+	zapOpts := []zap.Option{
+		zap.AddCaller(),
+		zap.AddCallerSkip(1),
+	}
+	zapOpts = append(zapOpts, opts...)
+
+	zapLogger := zap.New(core, zapOpts...)
 
 	return &Logger{
-		logger:  zapLogger.Sugar(),
-		piiMode: conf.PIIMode,
+		logger:   zapLogger.Sugar(),
+		base:     newFieldsBase(zapLogger.Sugar()),
+		root:     zapLogger.Sugar(),
+		piiMode:  piiMode,
+		level:    atomicLevel,
+		stackKey: encoderConfig.StacktraceKey,
+		stats:    logStats,
 	}, nil
 }
 
 // Debug logs all inputs on the debug level.
 func (l *Logger) Debug(v ...any) {
-	handleUninitialized(l)
+	l = handleUninitialized(l)
 	l.logger.Debug(v...)
 }
 
 // Debugf formats and logs all inputs on the debug level.
 func (l *Logger) Debugf(format string, v ...any) {
-	handleUninitialized(l)
+	l = handleUninitialized(l)
 	l.logger.Debugf(format, v...)
 }
 
+// Debugln logs all inputs on the debug level, always adding spaces
+// between them like fmt.Println.
+func (l *Logger) Debugln(v ...any) {
+	l = handleUninitialized(l)
+	l.logger.Debugln(v...)
+}
+
 // Debugw logs all inputs and fields on the debug level.
 func (l *Logger) Debugw(msg string, keyValuePairs ...any) {
-	handleUninitialized(l)
-	l.logger.Debugw(msg, resolvePIIFunctions(l.piiMode, keyValuePairs)...)
+	l = handleUninitialized(l)
+	l.logger.Debugw(msg, l.resolveFields(keyValuePairs)...)
+}
+
+// DPanic logs all inputs on the dpanic level. In development mode, the
+// logger panics afterwards.
+func (l *Logger) DPanic(v ...any) {
+	l = handleUninitialized(l)
+	l.logger.DPanic(v...)
+}
+
+// DPanicf formats and logs all inputs on the dpanic level. In
+// development mode, the logger panics afterwards.
+func (l *Logger) DPanicf(format string, v ...any) {
+	l = handleUninitialized(l)
+	l.logger.DPanicf(format, v...)
+}
+
+// DPanicln logs all inputs on the dpanic level, always adding spaces
+// between them like fmt.Println. In development mode, the logger
+// panics afterwards.
+func (l *Logger) DPanicln(v ...any) {
+	l = handleUninitialized(l)
+	l.logger.DPanicln(v...)
+}
+
+// DPanicw logs all inputs and fields on the dpanic level. In
+// development mode, the logger panics afterwards.
+func (l *Logger) DPanicw(msg string, keyValuePairs ...any) {
+	l = handleUninitialized(l)
+	l.logger.DPanicw(msg, l.resolveFields(keyValuePairs)...)
 }
 
 // Error logs all inputs on the error level.
 func (l *Logger) Error(v ...any) {
-	handleUninitialized(l)
+	l = handleUninitialized(l)
 	l.logger.Error(v...)
 }
 
 // Errorf formats and logs all inputs on the error level.
 func (l *Logger) Errorf(format string, v ...any) {
-	handleUninitialized(l)
+	l = handleUninitialized(l)
 	l.logger.Errorf(format, v...)
 }
 
+// Errorln logs all inputs on the error level, always adding spaces
+// between them like fmt.Println.
+func (l *Logger) Errorln(v ...any) {
+	l = handleUninitialized(l)
+	l.logger.Errorln(v...)
+}
+
 // Errorw logs all inputs and fields on the error level.
 func (l *Logger) Errorw(msg string, keyValuePairs ...any) {
-	handleUninitialized(l)
-	l.logger.Errorw(msg, resolvePIIFunctions(l.piiMode, keyValuePairs)...)
+	l = handleUninitialized(l)
+	l.logger.Errorw(msg, l.resolveFields(keyValuePairs)...)
 }
 
 // Fatal logs all inputs on the fatal level and runs os.exit(1) at
 // the end.
 func (l *Logger) Fatal(v ...any) {
-	handleUninitialized(l)
+	l = handleUninitialized(l)
 	l.logger.Fatal(v...)
 }
 
 // Fatalf formats and logs all inputs on the fatal level and runs
 // os.exit(1) at the end.
 func (l *Logger) Fatalf(format string, v ...any) {
-	handleUninitialized(l)
+	l = handleUninitialized(l)
 	l.logger.Fatalf(format, v...)
 }
 
+// Fatalln logs all inputs on the fatal level, always adding spaces
+// between them like fmt.Println and runs os.exit(1) at the end.
+func (l *Logger) Fatalln(v ...any) {
+	l = handleUninitialized(l)
+	l.logger.Fatalln(v...)
+}
+
 // Fatalw logs all inputs and fields on the fatal level and runs
 // os.exit(1) at the end.
 func (l *Logger) Fatalw(msg string, keyValuePairs ...any) {
-	handleUninitialized(l)
-	l.logger.Fatalw(msg, resolvePIIFunctions(l.piiMode, keyValuePairs)...)
+	l = handleUninitialized(l)
+	l.logger.Fatalw(msg, l.resolveFields(keyValuePairs)...)
 }
 
 // Info logs all inputs on the info level.
 func (l *Logger) Info(v ...any) {
-	handleUninitialized(l)
+	l = handleUninitialized(l)
 	l.logger.Info(v...)
 }
 
 // Infof formats and logs all inputs on the info level.
 func (l *Logger) Infof(format string, v ...any) {
-	handleUninitialized(l)
+	l = handleUninitialized(l)
 	l.logger.Infof(format, v...)
 }
 
+// Infoln logs all inputs on the info level, always adding spaces
+// between them like fmt.Println.
+func (l *Logger) Infoln(v ...any) {
+	l = handleUninitialized(l)
+	l.logger.Infoln(v...)
+}
+
 // Infow logs all inputs and fields on the info level.
 func (l *Logger) Infow(msg string, keyValuePairs ...any) {
-	handleUninitialized(l)
-	fields := resolvePIIFunctions(l.piiMode, keyValuePairs)
+	l = handleUninitialized(l)
+	fields := l.resolveFields(keyValuePairs)
 	l.logger.Infow(msg, fields...)
 }
 
+// Panic logs all inputs on the panic level and panics afterwards.
+func (l *Logger) Panic(v ...any) {
+	l = handleUninitialized(l)
+	l.logger.Panic(v...)
+}
+
+// Panicf formats and logs all inputs on the panic level and panics
+// afterwards.
+func (l *Logger) Panicf(format string, v ...any) {
+	l = handleUninitialized(l)
+	l.logger.Panicf(format, v...)
+}
+
+// Panicln logs all inputs on the panic level, always adding spaces
+// between them like fmt.Println and panics afterwards.
+func (l *Logger) Panicln(v ...any) {
+	l = handleUninitialized(l)
+	l.logger.Panicln(v...)
+}
+
+// Panicw logs all inputs and fields on the panic level and panics
+// afterwards.
+func (l *Logger) Panicw(msg string, keyValuePairs ...any) {
+	l = handleUninitialized(l)
+	l.logger.Panicw(msg, l.resolveFields(keyValuePairs)...)
+}
+
 func (l *Logger) Sync() error {
-	handleUninitialized(l)
+	l = handleUninitialized(l)
 
 	return l.logger.Sync()
 }
 
+// Flush syncs the logger like Sync, but returns the error of the context
+// once it is done, e.g. to bound the time spent on flushing a network
+// writer during shutdown. The sync continues in the background then.
+func (l *Logger) Flush(ctx context.Context) error {
+	l = handleUninitialized(l)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- l.logger.Sync()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 // Warn logs all inputs on the warn level.
 func (l *Logger) Warn(v ...any) {
-	handleUninitialized(l)
+	l = handleUninitialized(l)
 	l.logger.Warn(v...)
 }
 
 // Warnf formats and logs all inputs on the warn level.
 func (l *Logger) Warnf(format string, v ...any) {
-	handleUninitialized(l)
+	l = handleUninitialized(l)
 	l.logger.Warnf(format, v...)
 }
 
+// Warnln logs all inputs on the warn level, always adding spaces
+// between them like fmt.Println.
+func (l *Logger) Warnln(v ...any) {
+	l = handleUninitialized(l)
+	l.logger.Warnln(v...)
+}
+
 // Warnw logs all inputs and fields on the warn level.
 func (l *Logger) Warnw(msg string, keyValuePairs ...any) {
-	handleUninitialized(l)
-	l.logger.Warnw(msg, resolvePIIFunctions(l.piiMode, keyValuePairs)...)
+	l = handleUninitialized(l)
+	l.logger.Warnw(msg, l.resolveFields(keyValuePairs)...)
 }
 
-// With returns a pointer to a new logger containing the added fields.
-func (l *Logger) With(keyValuePairs ...any) *Logger {
-	handleUninitialized(l)
+// With returns a new logger containing the added fields.
+func (l *Logger) With(keyValuePairs ...any) ILogger {
+	l = handleUninitialized(l)
 
-	return &Logger{
-		logger:  l.logger.With(resolvePIIFunctions(l.piiMode, keyValuePairs)...),
-		piiMode: l.piiMode,
+	return l.withFields(toFields(l.resolveFields(keyValuePairs))...)
+}
+
+// WithFields returns a new logger containing the given fields. The
+// fields are added in the order of their keys, so that the output is
+// stable regardless of the map iteration order. PII values are resolved
+// according to the PII mode and keep the key they have been created
+// with.
+func (l *Logger) WithFields(fields map[string]any) ILogger {
+	l = handleUninitialized(l)
+
+	if len(fields) == 0 {
+		return l
+	}
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	keyValuePairs := make([]any, 0, 2*len(keys))
+	for _, key := range keys {
+		if resolver, ok := fields[key].(PIIResolver); ok {
+			keyValuePairs = append(keyValuePairs, resolver)
+
+			continue
+		}
+
+		keyValuePairs = append(keyValuePairs, key, fields[key])
+	}
+
+	return l.withFields(toFields(l.resolveFields(keyValuePairs))...)
+}
+
+// Namespace returns a new logger, which nests all fields added
+// afterwards, either via With or on a log statement, under the given
+// key.
+func (l *Logger) Namespace(name string) ILogger {
+	l = handleUninitialized(l)
+
+	return l.withFields(zap.Namespace(name))
+}
+
+// Desugar returns the underlying zap logger, e.g. for libraries, which
+// require a *zap.Logger. It shares the configuration and output of the
+// logger, but fields logged via the zap logger bypass PII resolution.
+func (l *Logger) Desugar() *zap.Logger {
+	l = handleUninitialized(l)
+
+	return l.logger.Desugar().WithOptions(zap.AddCallerSkip(-1))
+}
+
+// Close flushes any buffered logs and releases the resources held by
+// the logger, i.e. it closes the writers of the outputs, which implement
+// io.Closer, e.g. files, except for stdout and stderr. Loggers derived
+// via With share these resources, so Close shall only be called once the
+// logger and all loggers derived from it are not used anymore.
+func (l *Logger) Close() error {
+	l = handleUninitialized(l)
+
+	var errs error
+
+	if err := l.logger.Sync(); err != nil {
+		errs = multierr.Append(errs, err)
+	}
+
+	for _, closeFunc := range l.closeFuncs {
+		errs = multierr.Append(errs, closeFunc())
+	}
+
+	return errs
+}
+
+// Merge returns a new logger containing the fields added to the logger
+// via With and similar methods followed by the ones added to the other
+// logger. If both loggers hold a field with the same key, the field of
+// the other logger wins. Everything else, e.g. the level, the PII mode
+// and the outputs, is taken from the logger, so fields added to the
+// other logger are resolved according to its PII mode. Fields added to
+// the other logger via WithLazy are not merged, and loggers of other
+// ILogger implementations are ignored.
+func (l *Logger) Merge(other ILogger) ILogger {
+	l = handleUninitialized(l)
+
+	otherLogger, ok := other.(*Logger)
+	if !ok || otherLogger == nil || len(otherLogger.fields) == 0 {
+		return l
+	}
+
+	keys := make(map[string]struct{}, len(otherLogger.fields))
+	for _, f := range otherLogger.fields {
+		if f.Type != zapcore.NamespaceType {
+			keys[f.Key] = struct{}{}
+		}
 	}
+
+	fields := make([]zap.Field, 0, len(l.fields)+len(otherLogger.fields))
+	for _, f := range l.fields {
+		if _, ok := keys[f.Key]; ok && f.Type != zapcore.NamespaceType {
+			continue
+		}
+
+		fields = append(fields, f)
+	}
+
+	fields = append(fields, otherLogger.fields...)
+
+	return l.withRebuiltFields(fields)
+}
+
+// Fields returns the fields added to the logger via With and similar
+// methods in the order they have been added. PII fields are returned
+// resolved. Fields added via WithLazy are not included.
+func (l *Logger) Fields() []Field {
+	l = handleUninitialized(l)
+
+	fields := make([]Field, len(l.fields))
+	copy(fields, l.fields)
+
+	return fields
+}
+
+// Without returns a new logger without the fields with the given keys,
+// which have been added to the logger via With and similar methods, e.g.
+// to drop a noisy request field for a single subsystem. Keys are matched
+// regardless of namespaces, but the namespaces themselves are kept. Fields added via WithLazy cannot be removed.
+func (l *Logger) Without(keys ...string) ILogger {
+	l = handleUninitialized(l)
+
+	if len(keys) == 0 || len(l.fields) == 0 {
+		return l
+	}
+
+	removed := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		removed[key] = struct{}{}
+	}
+
+	fields := make([]zap.Field, 0, len(l.fields))
+	for _, f := range l.fields {
+		if _, ok := removed[f.Key]; ok && f.Type != zapcore.NamespaceType {
+			continue
+		}
+
+		fields = append(fields, f)
+	}
+
+	if len(fields) == len(l.fields) {
+		return l
+	}
+
+	return l.withRebuiltFields(fields)
+}
+
+// withSugaredLogger returns a copy of the logger using the given
+// sugared logger.
+func (l *Logger) withSugaredLogger(sugaredLogger *zap.SugaredLogger) *Logger {
+	out := *l
+	out.logger = sugaredLogger
+	out.base = newFieldsBase(sugaredLogger)
+
+	return &out
+}
+
+// withFields returns a copy of the logger, which adds the given fields
+// to all log statements. The fields are tracked in addition to the zap
+// logger, because zap does not expose the fields it has accumulated.
+func (l *Logger) withFields(fields ...zap.Field) *Logger {
+	out := l.withSugaredLogger(l.logger.Desugar().With(fields...).Sugar())
+
+	out.fields = make([]zap.Field, 0, len(l.fields)+len(fields))
+	out.fields = append(out.fields, l.fields...)
+	out.fields = append(out.fields, fields...)
+
+	return out
 }
 
-func handleUninitialized(l *Logger) {
-	if l == nil {
+// withRebuiltFields returns a copy of the logger, which adds the given
+// fields to its root logger instead of the ones accumulated so far.
+func (l *Logger) withRebuiltFields(fields []zap.Field) *Logger {
+	out := l.withSugaredLogger(l.root.Desugar().With(fields...).Sugar())
+	out.fields = fields
+
+	return out
+}
+
+// withWrappedCore returns a copy of the logger, whose core is wrapped by
+// the given function. The root logger is wrapped as well, so that the
+// accumulated fields can be rebuilt on top of it.
+func (l *Logger) withWrappedCore(wrap func(core zapcore.Core) zapcore.Core) *Logger {
+	out := l.withSugaredLogger(l.logger.Desugar().WithOptions(zap.WrapCore(wrap)).Sugar())
+	out.root = l.root.Desugar().WithOptions(zap.WrapCore(wrap)).Sugar()
+
+	return out
+}
+
+// handleUninitialized returns the package-level logger in place of an
+// uninitialized logger and warns about it once. If PanicOnUninitialized
+// is set, it panics instead.
+func handleUninitialized(l *Logger) *Logger {
+	if l != nil {
+		return l
+	}
+
+	if PanicOnUninitialized {
 		ephemeralLogger := zap.Must(zap.NewProduction(zap.AddCallerSkip(1), zap.AddStacktrace(zapcore.FatalLevel)))
 		ephemeralLogger.Panic("logger has not been initialized - panicking")
 	}
+
+	fallback := packageLogger()
+
+	uninitializedWarning.Do(func() {
+		fallback.logger.Desugar().WithOptions(zap.WithCaller(false)).Warn("logger has not been initialized - falling back to the package-level logger")
+	})
+
+	return fallback
 }
 
 // The PIIResolver interface is what the logger checks against,
 // when trying to resolve PII fields in log statements before writing
-// the logs.
+// the logs. It can be implemented to provide custom PII field types,
+// which need more than the string value CustomPII offers.
 type PIIResolver interface {
-	resolve(piiMode PIIMode) zap.Field
+	Resolve(piiMode PIIMode) zap.Field
+}
+
+// The piiCipherResolver interface is implemented by the PII fields of
+// this package, which additionally need the cipher of the logger in
+// PIIModeEncrypt.
+type piiCipherResolver interface {
+	resolve(piiMode PIIMode, piiCipher cipher.AEAD) zap.Field
 }
 
-func resolvePIIFunctions(piiMode PIIMode, keyValuePairs []any) []any {
-	out := make([]any, 0)
+// resolveFields prepares key-value pairs for logging by resolving PII
+// fields, dropping filtered keys, truncating long values and handling
+// collisions with reserved keys.
+func (l *Logger) resolveFields(keyValuePairs []any) []any {
+	out := resolvePIIFunctions(l.piiMode, l.piiCipher, l.stats, keyValuePairs)
+
+	if l.keyFilter != nil {
+		out = l.keyFilter.filter(out)
+	}
+
+	if l.maxFieldValueBytes > 0 {
+		out = truncateFields(l.maxFieldValueBytes, out)
+	}
+
+	if l.reservedKeys != nil {
+		out = l.reservedKeys.handle(l, out)
+	}
+
+	return out
+}
+
+// resolvePIIFunctions resolves all PII fields and values of types
+// registered via RegisterPIIType in the key-value pairs and counts them
+// in the stats, if given. Additionally, durations and points in time are
+// converted to typed fields, so they are encoded the same way as the ones
+// of the logger. A dangling element without a value is kept under badKey
+// together with an error field instead of being dropped.
+func resolvePIIFunctions(piiMode PIIMode, piiCipher cipher.AEAD, s *stats, keyValuePairs []any) []any {
+	out := make([]any, 0, len(keyValuePairs))
+
+	for i := 0; i < len(keyValuePairs); i++ {
+		element := keyValuePairs[i]
+
+		if e, ok := element.(piiCipherResolver); ok {
+			out = append(out, e.resolve(piiMode, piiCipher))
+			s.countPIIResolved(piiMode)
+
+			continue
+		}
 
-	for _, element := range keyValuePairs {
 		if e, ok := element.(PIIResolver); ok {
-			out = append(out, e.resolve(piiMode))
+			out = append(out, e.Resolve(piiMode))
+			s.countPIIResolved(piiMode)
+
+			continue
+		}
+
+		if f, ok := element.(zap.Field); ok {
+			if e, ok := piiFieldResolver(f); ok {
+				out = append(out, e.resolve(piiMode, piiCipher))
+				s.countPIIResolved(piiMode)
+
+				continue
+			}
+
+			out = append(out, element)
+
+			continue
+		}
+
+		if i == len(keyValuePairs)-1 {
+			out = append(out, zap.Any(badKey, element), zap.String(argErrorKey, oddKeyValuePairsError))
+
+			continue
+		}
+
+		key, ok := element.(string)
+		if !ok {
+			out = append(out, element)
 
 			continue
 		}
 
-		out = append(out, element)
+		i++
+
+		if f, ok := resolvePIIType(piiMode, key, keyValuePairs[i]); ok {
+			out = append(out, f)
+			s.countPIIResolved(piiMode)
+
+			continue
+		}
+
+		switch value := keyValuePairs[i].(type) {
+		case time.Duration:
+			out = append(out, zap.Duration(key, value))
+		case time.Time:
+			out = append(out, zap.Time(key, value))
+		default:
+			out = append(out, key, value)
+		}
 	}
 
 	return out
@@ -337,85 +1305,284 @@ func validateLoggerConf(conf Configuration) error {
 		return errors.New("invalid PII mode in logger configuration")
 	}
 
+	if conf.PIIMode == PIIModeEncrypt {
+		if len(conf.PIIEncryptionKey) == 0 {
+			return errors.New("missing PII encryption key in logger configuration")
+		}
+
+		if _, err := newPIICipher(conf.PIIEncryptionKey); err != nil {
+			return errors.Wrap(err, "invalid PII encryption key in logger configuration")
+		}
+	}
+
 	if _, ok := outputModes[conf.OutputMode]; !ok {
 		return errors.New("invalid output mode in logger configuration")
 	}
 
+	if conf.MaxFieldValueBytes < 0 {
+		return errors.New("invalid maximum field value length in logger configuration")
+	}
+
+	if _, ok := formats[conf.Format]; !ok {
+		return errors.New("invalid format in logger configuration")
+	}
+
+	if conf.StacktraceLevel != nil {
+		if _, ok := logLevels[*conf.StacktraceLevel]; !ok {
+			return errors.New("invalid stacktrace level in logger configuration")
+		}
+	}
+
+	if conf.StdErrThreshold != nil {
+		if _, ok := logLevels[*conf.StdErrThreshold]; !ok {
+			return errors.New("invalid stderr threshold in logger configuration")
+		}
+	}
+
+	if conf.Output != nil && isNilWriter(conf.Output) {
+		return errors.New("invalid nil output writer in logger configuration")
+	}
+
+	if _, ok := levelEncodings[conf.LevelEncoding]; !ok {
+		return errors.New("invalid level encoding in logger configuration")
+	}
+
+	if conf.BufferSize < 0 {
+		return errors.New("invalid negative buffer size in logger configuration")
+	}
+
+	if conf.FlushInterval < 0 {
+		return errors.New("invalid negative flush interval in logger configuration")
+	}
+
+	if conf.Sampling != nil {
+		if err := validateSamplingConf(*conf.Sampling); err != nil {
+			return errors.Wrap(err, "invalid sampling in logger configuration")
+		}
+	}
+
+	if conf.Deduplication != nil {
+		if err := validateDeduplicationConf(*conf.Deduplication); err != nil {
+			return errors.Wrap(err, "invalid deduplication in logger configuration")
+		}
+	}
+
+	if _, ok := keyCollisionModes[conf.OnReservedKeyCollision]; !ok {
+		return errors.New("invalid reserved key collision mode in logger configuration")
+	}
+
+	if conf.Syslog != nil {
+		if _, ok := syslogFacilities[conf.Syslog.Facility]; !ok {
+			return errors.New("invalid syslog facility in logger configuration")
+		}
+	}
+
+	if err := validateRateLimitConf(conf); err != nil {
+		return errors.Wrap(err, "invalid rate limit in logger configuration")
+	}
+
+	for i, sink := range conf.Sinks {
+		if err := validateSink(sink); err != nil {
+			return errors.Wrapf(err, "invalid sink at index %d in logger configuration", i)
+		}
+	}
+
 	return nil
 }
 
-func createCore(mode OutputMode, minLevel Level, stdErrThresholdLevel zapcore.Level) zapcore.Core {
-	minLvl := zapcore.Level(minLevel)
+// formatEncoderConfig returns the encoder configuration of the format
+// including the key names of the configuration, if they apply.
+func formatEncoderConfig(format Format, conf Configuration) zapcore.EncoderConfig {
+	switch format {
+	case FormatGELF:
+		return gelfEncoderConfig
+	case FormatGCP:
+		return gcpEncoderConfig
+	case FormatLogstash:
+		return logstashEncoderConfig
+	default:
+		encConf := getEncoderConfig(conf.KeyNames)
+		encConf.EncodeLevel = levelEncodings[conf.LevelEncoding]
+
+		return encConf
+	}
+}
+
+func newEncoder(format Format, conf Configuration) (zapcore.Encoder, error) {
+	encConf := formatEncoderConfig(format, conf)
+
+	if !conf.IncludeFunction {
+		encConf.FunctionKey = ""
+	}
+
+	if conf.FullCaller {
+		encConf.EncodeCaller = zapcore.FullCallerEncoder
+	}
 
-	if mode == OutputStdOut || mode == OutputStdErr {
-		all := zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
-			return lvl >= minLvl
-		})
+	if conf.NewReflectedEncoder != nil {
+		encConf.NewReflectedEncoder = conf.NewReflectedEncoder
+	}
 
-		var output zapcore.WriteSyncer
+	if conf.LineEnding != "" {
+		encConf.LineEnding = conf.LineEnding
+	}
 
-		if mode == OutputStdOut {
-			output = zapcore.Lock(os.Stdout)
-		} else {
-			output = zapcore.Lock(os.Stderr)
+	encConf.SkipLineEnding = conf.SkipLineEnding
+
+	switch format {
+	case FormatGELF:
+		return newGELFEncoder(encConf)
+	case FormatLogfmt:
+		return newLogfmtEncoder(encConf), nil
+	case FormatLogstash:
+		return newLogstashEncoder(encConf), nil
+	default:
+		encoder := zapcore.NewJSONEncoder(encConf)
+		if conf.PrettyJSON && format == FormatJSON {
+			lineEnding := encConf.LineEnding
+			if encConf.SkipLineEnding {
+				lineEnding = ""
+			}
+
+			return &prettyJSONEncoder{
+				Encoder:    encoder,
+				lineEnding: lineEnding,
+			}, nil
 		}
 
-		jsonEncoder := zapcore.NewJSONEncoder(encoderConfig)
+		return encoder, nil
+	}
+}
 
-		return zapcore.NewCore(jsonEncoder, output, all)
+// newSinkEncoder creates the encoder for a single sink including all
+// additions to the format, which are enabled in the configuration.
+func newSinkEncoder(format Format, conf Configuration) (zapcore.Encoder, error) {
+	encoder, err := newEncoder(format, conf)
+	if err != nil {
+		return nil, err
 	}
 
-	// Define our level-handling logic to differentiate priority based on log level
-	highPriority := zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
-		return lvl >= stdErrThresholdLevel && lvl >= minLvl
-	})
-	lowPriority := zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
-		return lvl < stdErrThresholdLevel && lvl >= minLvl
-	})
+	if conf.IncludeSeverityNumber {
+		encoder = &severityNumberEncoder{Encoder: encoder}
+	}
 
-	// Create separate outputs for the different priorities.
-	lowPrioOut := zapcore.Lock(os.Stdout)
-	highPrioOut := zapcore.Lock(os.Stderr)
-	jsonEncoder := zapcore.NewJSONEncoder(encoderConfig)
+	return encoder, nil
+}
+
+func createCore(conf Configuration) (zapcore.Core, []func() error, error) {
+	sinks := conf.Sinks
+	if len(sinks) == 0 {
+		threshold := WarnLevel
+		if conf.StdErrThreshold != nil {
+			threshold = *conf.StdErrThreshold
+		}
+
+		sinks = defaultSinks(conf, threshold)
+	}
+
+	cores := make([]zapcore.Core, 0, len(sinks))
+	closeFuncs := make([]func() error, 0)
+	closers := make([]io.Closer, 0)
+
+	for _, sink := range sinks {
+		encoder, err := newSinkEncoder(sink.Format, conf)
+		if err != nil {
+			return nil, nil, err
+		}
 
-	// tie it together
-	core := zapcore.NewTee(
-		zapcore.NewCore(jsonEncoder, lowPrioOut, lowPriority),
-		zapcore.NewCore(jsonEncoder, highPrioOut, highPriority),
-	)
+		output := zapcore.Lock(zapcore.AddSync(sink.Writer))
+
+		// The buffer has to be flushed before the gzip stream is closed.
+		sinkCloseFuncs := make([]func() error, 0, 2)
+
+		if sink.Compress {
+			compressed := newGzipWriteSyncer(output)
+			sinkCloseFuncs = append(sinkCloseFuncs, compressed.Close)
+			output = compressed
+		}
+
+		if conf.BufferedWrites {
+			buffered := &zapcore.BufferedWriteSyncer{
+				WS:            output,
+				Size:          conf.BufferSize,
+				FlushInterval: conf.FlushInterval,
+			}
+			sinkCloseFuncs = append([]func() error{buffered.Stop}, sinkCloseFuncs...)
+			output = buffered
+		}
+
+		closeFuncs = append(closeFuncs, sinkCloseFuncs...)
+		closers = appendCloser(closers, sink.Writer)
+
+		cores = append(cores, zapcore.NewCore(encoder, output, sink.levelEnabler()))
+	}
+
+	// The writers are closed after all buffers and gzip streams have been
+	// flushed into them.
+	for _, closer := range closers {
+		closeFuncs = append(closeFuncs, closer.Close)
+	}
+
+	if conf.RingBuffer != nil {
+		encoder, err := newSinkEncoder(conf.Format, conf)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		cores = append(cores, zapcore.NewCore(encoder, zapcore.AddSync(conf.RingBuffer), zapcore.DebugLevel))
+	}
+
+	if conf.Syslog != nil {
+		encoder, err := newSinkEncoder(conf.Format, conf)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		syslogCore, closeFunc, err := newSyslogCore(*conf.Syslog, encoder)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		cores = append(cores, syslogCore)
+		closeFuncs = append(closeFuncs, closeFunc)
+	}
+
+	if len(cores) == 1 {
+		return cores[0], closeFuncs, nil
+	}
 
-	return core
+	return zapcore.NewTee(cores...), closeFuncs, nil
 }
 
 func getEncoderConfig(keyNames KeyNames) zapcore.EncoderConfig {
 	out := encoderConfig
 
 	if keyNames.MessageKey != "" {
-		encoderConfig.MessageKey = keyNames.MessageKey
+		out.MessageKey = keyNames.MessageKey
 	}
 
 	if keyNames.LevelKey != "" {
-		encoderConfig.LevelKey = keyNames.LevelKey
+		out.LevelKey = keyNames.LevelKey
 	}
 
 	if keyNames.TimeKey != "" {
-		encoderConfig.TimeKey = keyNames.TimeKey
+		out.TimeKey = keyNames.TimeKey
 	}
 
 	if keyNames.NameKey != "" {
-		encoderConfig.NameKey = keyNames.NameKey
+		out.NameKey = keyNames.NameKey
 	}
 
 	if keyNames.CallerKey != "" {
-		encoderConfig.CallerKey = keyNames.CallerKey
+		out.CallerKey = keyNames.CallerKey
 	}
 
 	if keyNames.FunctionKey != "" {
-		encoderConfig.FunctionKey = keyNames.FunctionKey
+		out.FunctionKey = keyNames.FunctionKey
 	}
 
 	if keyNames.StacktraceKey != "" {
-		encoderConfig.StacktraceKey = keyNames.StacktraceKey
+		out.StacktraceKey = keyNames.StacktraceKey
 	}
 
 	return out
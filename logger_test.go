@@ -0,0 +1,338 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// syncBuffer is a writer, which can be written to and read from
+// concurrently.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.String()
+}
+
+// lines decodes the JSON log statements written to the buffer.
+func (b *syncBuffer) lines(t *testing.T) []map[string]any {
+	t.Helper()
+
+	var out []map[string]any
+
+	for _, line := range strings.Split(strings.TrimSpace(b.String()), "\n") {
+		if line == "" {
+			continue
+		}
+
+		entry := make(map[string]any)
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("invalid log line %q: %v", line, err)
+		}
+
+		out = append(out, entry)
+	}
+
+	return out
+}
+
+// newTestLogger creates a logger writing JSON to the returned buffer.
+func newTestLogger(t *testing.T, conf Configuration) (*Logger, *syncBuffer) {
+	t.Helper()
+
+	buf := &syncBuffer{}
+	conf.Output = buf
+
+	l, err := NewLogger(conf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return l, buf
+}
+
+func TestWithFamilyChains(t *testing.T) {
+	l, buf := newTestLogger(t, Configuration{})
+
+	var chained ILogger = l.With("k", "v").
+		WithError(errors.New("boom")).
+		WithFields(map[string]any{"f": 1}).
+		WithLazy("lazy", true).
+		Namespace("ns")
+	chained.Infow("chained", "inner", 2)
+
+	lines := buf.lines(t)
+	if len(lines) != 1 {
+		t.Fatalf("expected 1 log line, got %d: %s", len(lines), buf.String())
+	}
+
+	for key, want := range map[string]any{"k": "v", "error": "boom", "f": float64(1), "lazy": true} {
+		if got := lines[0][key]; got != want {
+			t.Errorf("expected %q to be %v, got %v", key, want, got)
+		}
+	}
+
+	ns, _ := lines[0]["ns"].(map[string]any)
+	if got := ns["inner"]; got != float64(2) {
+		t.Errorf("expected inner field in the namespace, got %v", lines[0]["ns"])
+	}
+}
+
+func TestDerivationChains(t *testing.T) {
+	l, buf := newTestLogger(t, Configuration{MinimumLogLevel: InfoLevel})
+	other, _ := newTestLogger(t, Configuration{})
+
+	extra := &syncBuffer{}
+	extraCore := zapcore.NewCore(zapcore.NewJSONEncoder(encoderConfig), zapcore.AddSync(extra), zapcore.DebugLevel)
+
+	var chained ILogger = l.With("k", "v", "drop", true).
+		WithContext(context.Background()).
+		WithLevel(DebugLevel).
+		WithPIIMode(PIIModeHash).
+		Without("drop").
+		Merge(other.With("merged", 1)).
+		Tee(extraCore)
+	chained.Debugw("chained", PII("email", "jane@example.com"))
+
+	for _, b := range []*syncBuffer{buf, extra} {
+		lines := b.lines(t)
+		if len(lines) != 1 {
+			t.Fatalf("expected 1 log line, got %d: %s", len(lines), b.String())
+		}
+
+		for key, want := range map[string]any{"k": "v", "merged": float64(1), "email": hash("jane@example.com")} {
+			if got := lines[0][key]; got != want {
+				t.Errorf("expected %q to be %v, got %v", key, want, got)
+			}
+		}
+
+		if _, ok := lines[0]["drop"]; ok {
+			t.Errorf("expected the dropped field to be removed: %s", b.String())
+		}
+	}
+}
+
+// setPackageLogger replaces the package-level logger for the duration of
+// the test.
+func setPackageLogger(t *testing.T, l *Logger) {
+	t.Helper()
+
+	loggerMu.Lock()
+	previous := logger
+	logger = l
+	loggerMu.Unlock()
+
+	t.Cleanup(func() {
+		loggerMu.Lock()
+		logger = previous
+		loggerMu.Unlock()
+	})
+}
+
+func TestUninitializedLoggerFallsBack(t *testing.T) {
+	const warning = "logger has not been initialized - falling back to the package-level logger"
+
+	fallback, buf := newTestLogger(t, Configuration{
+		MinimumLogLevel: DebugLevel,
+		// the hook replaces the exit, so that Fatal can be tested
+		ZapOptions: []zap.Option{zap.WithFatalHook(zapcore.WriteThenPanic)},
+	})
+	setPackageLogger(t, fallback)
+
+	uninitializedWarning = sync.Once{}
+
+	tests := []struct {
+		name   string
+		call   func(l ILogger)
+		logs   int
+		panics bool
+	}{
+		{name: "Debug", call: func(l ILogger) { l.Debug("msg") }, logs: 1},
+		{name: "Debugf", call: func(l ILogger) { l.Debugf("msg %d", 1) }, logs: 1},
+		{name: "Debugln", call: func(l ILogger) { l.Debugln("msg") }, logs: 1},
+		{name: "Debugw", call: func(l ILogger) { l.Debugw("msg", "k", "v") }, logs: 1},
+		{name: "DPanic", call: func(l ILogger) { l.DPanic("msg") }, logs: 1},
+		{name: "DPanicf", call: func(l ILogger) { l.DPanicf("msg %d", 1) }, logs: 1},
+		{name: "DPanicln", call: func(l ILogger) { l.DPanicln("msg") }, logs: 1},
+		{name: "DPanicw", call: func(l ILogger) { l.DPanicw("msg", "k", "v") }, logs: 1},
+		{name: "Error", call: func(l ILogger) { l.Error("msg") }, logs: 1},
+		{name: "Errorf", call: func(l ILogger) { l.Errorf("msg %d", 1) }, logs: 1},
+		{name: "Errorln", call: func(l ILogger) { l.Errorln("msg") }, logs: 1},
+		{name: "Errorw", call: func(l ILogger) { l.Errorw("msg", "k", "v") }, logs: 1},
+		{name: "Fatal", call: func(l ILogger) { l.Fatal("msg") }, logs: 1, panics: true},
+		{name: "Fatalf", call: func(l ILogger) { l.Fatalf("msg %d", 1) }, logs: 1, panics: true},
+		{name: "Fatalln", call: func(l ILogger) { l.Fatalln("msg") }, logs: 1, panics: true},
+		{name: "Fatalw", call: func(l ILogger) { l.Fatalw("msg", "k", "v") }, logs: 1, panics: true},
+		{name: "Info", call: func(l ILogger) { l.Info("msg") }, logs: 1},
+		{name: "Infof", call: func(l ILogger) { l.Infof("msg %d", 1) }, logs: 1},
+		{name: "Infoln", call: func(l ILogger) { l.Infoln("msg") }, logs: 1},
+		{name: "Infow", call: func(l ILogger) { l.Infow("msg", "k", "v") }, logs: 1},
+		{name: "Log", call: func(l ILogger) { l.Log(InfoLevel, "msg") }, logs: 1},
+		{name: "Logf", call: func(l ILogger) { l.Logf(InfoLevel, "msg %d", 1) }, logs: 1},
+		{name: "Logw", call: func(l ILogger) { l.Logw(InfoLevel, "msg", "k", "v") }, logs: 1},
+		{name: "Panic", call: func(l ILogger) { l.Panic("msg") }, logs: 1, panics: true},
+		{name: "Panicf", call: func(l ILogger) { l.Panicf("msg %d", 1) }, logs: 1, panics: true},
+		{name: "Panicln", call: func(l ILogger) { l.Panicln("msg") }, logs: 1, panics: true},
+		{name: "Panicw", call: func(l ILogger) { l.Panicw("msg", "k", "v") }, logs: 1, panics: true},
+		{name: "RecoverAndLog", call: func(l ILogger) { l.RecoverAndLog("msg") }},
+		{name: "RecoverAndRePanic", call: func(l ILogger) { l.RecoverAndRePanic("msg") }},
+		{name: "Sync", call: func(l ILogger) { _ = l.Sync() }},
+		{name: "Warn", call: func(l ILogger) { l.Warn("msg") }, logs: 1},
+		{name: "Warnf", call: func(l ILogger) { l.Warnf("msg %d", 1) }, logs: 1},
+		{name: "Warnln", call: func(l ILogger) { l.Warnln("msg") }, logs: 1},
+		{name: "Warnw", call: func(l ILogger) { l.Warnw("msg", "k", "v") }, logs: 1},
+		{name: "With", call: func(l ILogger) { l.With("k", "v").Info("msg") }, logs: 1},
+		{name: "WithError", call: func(l ILogger) { l.WithError(errors.New("boom")).Info("msg") }, logs: 1},
+		{name: "WithFields", call: func(l ILogger) { l.WithFields(map[string]any{"k": "v"}).Info("msg") }, logs: 1},
+		{name: "WithLazy", call: func(l ILogger) { l.WithLazy("k", "v").Info("msg") }, logs: 1},
+		{name: "Namespace", call: func(l ILogger) { l.Namespace("ns").Info("msg") }, logs: 1},
+		{name: "WithContext", call: func(l ILogger) { l.WithContext(context.Background()).Info("msg") }, logs: 1},
+		{name: "WithLevel", call: func(l ILogger) { l.WithLevel(WarnLevel).Info("msg") }},
+		{name: "WithPIIMode", call: func(l ILogger) { l.WithPIIMode(PIIModeHash).Info("msg") }, logs: 1},
+		{name: "Tee", call: func(l ILogger) { l.Tee(zapcore.NewNopCore()).Info("msg") }, logs: 1},
+		{name: "Merge", call: func(l ILogger) { l.Merge(nil).Info("msg") }, logs: 1},
+		{name: "Without", call: func(l ILogger) { l.Without("k").Info("msg") }, logs: 1},
+	}
+
+	countLogs := func() (logs, warnings int) {
+		for _, line := range buf.lines(t) {
+			if line["message"] == warning {
+				warnings++
+
+				continue
+			}
+
+			logs++
+		}
+
+		return logs, warnings
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var l *Logger
+
+			before, _ := countLogs()
+
+			func() {
+				defer func() {
+					if r := recover(); (r != nil) != tt.panics {
+						t.Errorf("expected panic to be %t, got %v", tt.panics, r)
+					}
+				}()
+
+				tt.call(l)
+			}()
+
+			if after, _ := countLogs(); after-before != tt.logs {
+				t.Errorf("expected %d log lines on the package-level logger, got %d", tt.logs, after-before)
+			}
+		})
+	}
+
+	if _, warnings := countLogs(); warnings != 1 {
+		t.Errorf("expected the warning to be logged once, got %d", warnings)
+	}
+}
+
+func TestUninitializedLoggerPanics(t *testing.T) {
+	PanicOnUninitialized = true
+	defer func() { PanicOnUninitialized = false }()
+
+	tests := []struct {
+		name string
+		call func(l *Logger)
+	}{
+		{name: "Infow", call: func(l *Logger) { l.Infow("msg") }},
+		{name: "With", call: func(l *Logger) { l.With("k", "v") }},
+		{name: "Sync", call: func(l *Logger) { _ = l.Sync() }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Error("expected a panic for an uninitialized logger")
+				}
+			}()
+
+			tt.call(nil)
+		})
+	}
+}
+
+func TestStacktraceLevel(t *testing.T) {
+	warn := WarnLevel
+
+	tests := []struct {
+		name  string
+		conf  Configuration
+		log   func(l *Logger)
+		stack bool
+	}{
+		{
+			name: "below the level",
+			conf: Configuration{StacktraceLevel: &warn},
+			log:  func(l *Logger) { l.Infow("msg") },
+		},
+		{
+			name:  "at the level",
+			conf:  Configuration{StacktraceLevel: &warn},
+			log:   func(l *Logger) { l.Warnw("msg") },
+			stack: true,
+		},
+		{
+			name:  "above the level",
+			conf:  Configuration{StacktraceLevel: &warn},
+			log:   func(l *Logger) { l.Errorw("msg") },
+			stack: true,
+		},
+		{
+			name:  "above the level via typed fields",
+			conf:  Configuration{StacktraceLevel: &warn},
+			log:   func(l *Logger) { l.ErrorFields("msg") },
+			stack: true,
+		},
+		{
+			name: "not set",
+			log:  func(l *Logger) { l.Errorw("msg") },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, buf := newTestLogger(t, tt.conf)
+
+			tt.log(l)
+
+			lines := buf.lines(t)
+			if len(lines) != 1 {
+				t.Fatalf("expected 1 log line, got %d: %s", len(lines), buf.String())
+			}
+
+			if _, ok := lines[0][encoderConfig.StacktraceKey]; ok != tt.stack {
+				t.Errorf("expected stack trace to be present: %t, got: %s", tt.stack, buf.String())
+			}
+		})
+	}
+}
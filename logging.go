@@ -1,85 +1,300 @@
 package log
 
-var logger = MustNewLogger(Configuration{MinimumLogLevel: DebugLevel})
+import (
+	"context"
+	"io"
+	"sync"
+
+	"go.uber.org/zap/zapcore"
+)
+
+var (
+	loggerMu sync.RWMutex
+	logger   = newPackageLogger(nil)
+)
+
+// newPackageLogger creates the package-level logger, which writes to the
+// given writer or, if nil, to stdout and stderr.
+func newPackageLogger(w io.Writer) *Logger {
+	return MustNewLogger(Configuration{MinimumLogLevel: DebugLevel, Output: w})
+}
+
+func packageLogger() *Logger {
+	loggerMu.RLock()
+	defer loggerMu.RUnlock()
+
+	return logger
+}
+
+// SetGlobalOutput redirects the package-level logger to the given
+// writer, e.g. to assert on the logs of the package-level functions in
+// tests, and returns a function restoring the previous logger. It is
+// safe to call SetGlobalOutput while the package-level logger is in use.
+func SetGlobalOutput(w io.Writer) (restore func()) {
+	redirected := newPackageLogger(w)
+
+	loggerMu.Lock()
+	previous := logger
+	logger = redirected
+	loggerMu.Unlock()
+
+	return func() {
+		loggerMu.Lock()
+		logger = previous
+		loggerMu.Unlock()
+	}
+}
 
 // Debug logs all inputs on the debug level.
 func Debug(v ...any) {
-	logger.Debug(v...)
+	packageLogger().Debug(v...)
 }
 
 // Debugf formats and logs all inputs on the debug level.
 func Debugf(format string, v ...any) {
-	logger.Debugf(format, v...)
+	packageLogger().Debugf(format, v...)
+}
+
+// Debugln logs all inputs on the debug level, always adding spaces
+// between them like fmt.Println.
+func Debugln(v ...any) {
+	packageLogger().Debugln(v...)
 }
 
 // Debugw logs all inputs and fields on the debug level.
 func Debugw(msg string, keyValuePairs ...any) {
-	logger.Debugw(msg, keyValuePairs...)
+	packageLogger().Debugw(msg, keyValuePairs...)
+}
+
+// DebugFields logs the message and typed fields on the debug level.
+func DebugFields(msg string, fields ...Field) {
+	packageLogger().writeFields(zapcore.DebugLevel, msg, fields)
+}
+
+// DPanic logs all inputs on the dpanic level.
+func DPanic(v ...any) {
+	packageLogger().DPanic(v...)
+}
+
+// DPanicf formats and logs all inputs on the dpanic level.
+func DPanicf(format string, v ...any) {
+	packageLogger().DPanicf(format, v...)
+}
+
+// DPanicln logs all inputs on the dpanic level, always adding spaces
+// between them like fmt.Println. In development mode, the logger
+// panics afterwards.
+func DPanicln(v ...any) {
+	packageLogger().DPanicln(v...)
+}
+
+// DPanicw logs all inputs and fields on the dpanic level.
+func DPanicw(msg string, keyValuePairs ...any) {
+	packageLogger().DPanicw(msg, keyValuePairs...)
+}
+
+// DPanicFields logs the message and typed fields on the dpanic level.
+func DPanicFields(msg string, fields ...Field) {
+	packageLogger().writeFields(zapcore.DPanicLevel, msg, fields)
 }
 
 // Error logs all inputs on the error level.
 func Error(v ...any) {
-	logger.Error(v...)
+	packageLogger().Error(v...)
 }
 
 // Errorf formats and logs all inputs on the error level.
 func Errorf(format string, v ...any) {
-	logger.Errorf(format, v...)
+	packageLogger().Errorf(format, v...)
+}
+
+// Errorln logs all inputs on the error level, always adding spaces
+// between them like fmt.Println.
+func Errorln(v ...any) {
+	packageLogger().Errorln(v...)
 }
 
 // Errorw logs all inputs and fields on the error level.
 func Errorw(msg string, keyValuePairs ...any) {
-	logger.Errorw(msg, keyValuePairs...)
+	packageLogger().Errorw(msg, keyValuePairs...)
+}
+
+// ErrorFields logs the message and typed fields on the error level.
+func ErrorFields(msg string, fields ...Field) {
+	packageLogger().writeFields(zapcore.ErrorLevel, msg, fields)
 }
 
 // Fatal logs all inputs on the fatal level and runs os.exit(1) at
 // the end.
 func Fatal(v ...any) {
-	logger.Fatal(v...)
+	packageLogger().Fatal(v...)
 }
 
 // Fatalf formats and logs all inputs on the fatal level and runs
 // os.exit(1) at the end.
 func Fatalf(format string, v ...any) {
-	logger.Fatalf(format, v...)
+	packageLogger().Fatalf(format, v...)
+}
+
+// Fatalln logs all inputs on the fatal level, always adding spaces
+// between them like fmt.Println and runs os.exit(1) at the end.
+func Fatalln(v ...any) {
+	packageLogger().Fatalln(v...)
 }
 
 // Fatalw logs all inputs and fields on the fatal level and runs
 // os.exit(1) at the end.
 func Fatalw(msg string, keyValuePairs ...any) {
-	logger.Fatalw(msg, keyValuePairs...)
+	packageLogger().Fatalw(msg, keyValuePairs...)
+}
+
+// FatalFields logs the message and typed fields on the fatal level and
+// runs os.exit(1) at the end.
+func FatalFields(msg string, fields ...Field) {
+	packageLogger().writeFields(zapcore.FatalLevel, msg, fields)
 }
 
 // Info logs all inputs on the info level.
 func Info(v ...any) {
-	logger.Info(v...)
+	packageLogger().Info(v...)
 }
 
 // Infof formats and logs all inputs on the info level.
 func Infof(format string, v ...any) {
-	logger.Infof(format, v...)
+	packageLogger().Infof(format, v...)
+}
+
+// Infoln logs all inputs on the info level, always adding spaces
+// between them like fmt.Println.
+func Infoln(v ...any) {
+	packageLogger().Infoln(v...)
 }
 
 // Infow logs all inputs and fields on the info level.
 func Infow(msg string, keyValuePairs ...any) {
-	logger.Infow(msg, keyValuePairs...)
+	packageLogger().Infow(msg, keyValuePairs...)
+}
+
+// DeprecatedOnce logs a warning about the usage of the deprecated
+// feature the first time it is called from a call site.
+func DeprecatedOnce(feature string) {
+	if !firstCallFromCallSite(2) {
+		return
+	}
+
+	packageLogger().logger.Warnw("deprecated feature used", "feature", feature)
+}
+
+// InfoFields logs the message and typed fields on the info level.
+func InfoFields(msg string, fields ...Field) {
+	packageLogger().writeFields(zapcore.InfoLevel, msg, fields)
+}
+
+// Log logs all inputs on the given level. Invalid levels are logged on
+// the info level.
+func Log(level Level, v ...any) {
+	packageLogger().Log(level, v...)
+}
+
+// Logw logs all inputs and fields on the given level. Invalid levels are
+// logged on the info level.
+func Logw(level Level, msg string, keyValuePairs ...any) {
+	packageLogger().Logw(level, msg, keyValuePairs...)
+}
+
+// Logf formats and logs all inputs on the given level. Invalid levels are
+// logged on the info level.
+func Logf(level Level, format string, v ...any) {
+	packageLogger().Logf(level, format, v...)
+}
+
+// Panic logs all inputs on the panic level and panics afterwards.
+func Panic(v ...any) {
+	packageLogger().Panic(v...)
+}
+
+// Panicf formats and logs all inputs on the panic level and panics
+// afterwards.
+func Panicf(format string, v ...any) {
+	packageLogger().Panicf(format, v...)
+}
+
+// Panicln logs all inputs on the panic level, always adding spaces
+// between them like fmt.Println and panics afterwards.
+func Panicln(v ...any) {
+	packageLogger().Panicln(v...)
+}
+
+// Panicw logs all inputs and fields on the panic level and panics
+// afterwards.
+func Panicw(msg string, keyValuePairs ...any) {
+	packageLogger().Panicw(msg, keyValuePairs...)
+}
+
+// PanicFields logs the message and typed fields on the panic level and
+// panics afterwards.
+func PanicFields(msg string, fields ...Field) {
+	packageLogger().writeFields(zapcore.PanicLevel, msg, fields)
+}
+
+// WarnFields logs the message and typed fields on the warn level.
+func WarnFields(msg string, fields ...Field) {
+	packageLogger().writeFields(zapcore.WarnLevel, msg, fields)
 }
 
 // Warn logs all inputs on the warn level.
 func Warn(v ...any) {
-	logger.Warn(v...)
+	packageLogger().Warn(v...)
 }
 
 // Warnf formats and logs all inputs on the warn level.
 func Warnf(format string, v ...any) {
-	logger.Warnf(format, v...)
+	packageLogger().Warnf(format, v...)
+}
+
+// Warnln logs all inputs on the warn level, always adding spaces
+// between them like fmt.Println.
+func Warnln(v ...any) {
+	packageLogger().Warnln(v...)
 }
 
 // Warnw logs all inputs and fields on the warn level.
 func Warnw(msg string, keyValuePairs ...any) {
-	logger.Warnw(msg, keyValuePairs...)
+	packageLogger().Warnw(msg, keyValuePairs...)
 }
 
 func Sync() error {
-	return logger.Sync()
+	return packageLogger().Sync()
+}
+
+// Flush syncs the package-level logger, but returns the error of the
+// context once it is done.
+func Flush(ctx context.Context) error {
+	return packageLogger().Flush(ctx)
+}
+
+// RecoverAndLog recovers from a panic and logs the recovered value
+// together with a stack trace on the error level. It has to be
+// deferred directly to work, e.g. defer log.RecoverAndLog("worker panicked").
+func RecoverAndLog(msg string) {
+	if r := recover(); r != nil {
+		l := packageLogger()
+		l.logRecovered(msg, r, l.rePanic)
+	}
+}
+
+// RecoverAndRePanic works like RecoverAndLog, but always panics again
+// with the recovered value after logging it. It has to be deferred
+// directly to work, e.g. defer log.RecoverAndRePanic("worker panicked").
+func RecoverAndRePanic(msg string) {
+	if r := recover(); r != nil {
+		packageLogger().logRecovered(msg, r, true)
+	}
+}
+
+// LogErr logs the message and fields together with the error on the
+// error level and returns the error. If the error is nil, nothing is
+// logged and nil is returned.
+func LogErr(err error, msg string, keyValuePairs ...any) error {
+	return packageLogger().LogErr(err, msg, keyValuePairs...)
 }
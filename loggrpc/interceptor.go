@@ -0,0 +1,90 @@
+// Package loggrpc provides gRPC server interceptors logging every RPC
+// via the log package. It is a separate module, so that the log package
+// itself does not depend on gRPC.
+package loggrpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/Rapix-x/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor creates an interceptor, which logs every unary
+// RPC with its method, status code, duration and peer. RPCs with the OK
+// status code are logged on the info level, all others on the error
+// level. The handler receives a request-scoped logger derived via
+// WithContext in its context, which can be retrieved via
+// log.FromContext.
+func UnaryServerInterceptor(l *log.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+
+		requestLogger := l.WithContext(ctx)
+		resp, err := handler(log.ContextWithLogger(ctx, requestLogger), req)
+
+		logRPC(ctx, requestLogger, info.FullMethod, err, time.Since(start))
+
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor creates an interceptor, which logs every
+// streaming RPC like UnaryServerInterceptor, once the stream has ended.
+func StreamServerInterceptor(l *log.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		ctx := stream.Context()
+
+		requestLogger := l.WithContext(ctx)
+		err := handler(srv, &serverStream{
+			ServerStream: stream,
+			ctx:          log.ContextWithLogger(ctx, requestLogger),
+		})
+
+		logRPC(ctx, requestLogger, info.FullMethod, err, time.Since(start))
+
+		return err
+	}
+}
+
+// logRPC logs a finished RPC on the level matching its status code.
+func logRPC(ctx context.Context, l log.ILogger, method string, err error, d time.Duration) {
+	code := status.Code(err)
+
+	level := log.InfoLevel
+	if code != codes.OK {
+		level = log.ErrorLevel
+	}
+
+	keyValuePairs := []any{
+		"method", method,
+		"code", code.String(),
+		log.DurationField("duration", d),
+	}
+
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		keyValuePairs = append(keyValuePairs, "peer", p.Addr.String())
+	}
+
+	if err != nil {
+		keyValuePairs = append(keyValuePairs, "error", err)
+	}
+
+	l.Logw(level, "handled rpc", keyValuePairs...)
+}
+
+// The serverStream passes the context holding the request-scoped logger
+// to stream handlers.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,119 @@
+package loggrpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/Rapix-x/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func newTestLogger(t *testing.T) (*log.Logger, *bytes.Buffer) {
+	t.Helper()
+
+	buf := &bytes.Buffer{}
+
+	l, err := log.NewLogger(log.Configuration{Output: buf})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return l, buf
+}
+
+func decode(t *testing.T, buf *bytes.Buffer) map[string]any {
+	t.Helper()
+
+	entry := make(map[string]any)
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("invalid log line %q: %v", buf.String(), err)
+	}
+
+	return entry
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		wantLevel string
+		wantCode  string
+	}{
+		{name: "ok", wantLevel: "info", wantCode: "OK"},
+		{name: "error", err: status.Error(codes.NotFound, "missing"), wantLevel: "error", wantCode: "NotFound"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, buf := newTestLogger(t)
+			info := &grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"}
+
+			var handlerLogger log.ILogger
+			_, err := UnaryServerInterceptor(l)(context.Background(), nil, info, func(ctx context.Context, _ any) (any, error) {
+				handlerLogger = log.FromContext(ctx)
+
+				return nil, tt.err
+			})
+			if err != tt.err {
+				t.Errorf("expected error %v, got %v", tt.err, err)
+			}
+
+			if handlerLogger != log.ILogger(l) {
+				t.Error("expected the handler context to hold the request-scoped logger")
+			}
+
+			entry := decode(t, buf)
+			if got := entry["severity"]; got != tt.wantLevel {
+				t.Errorf("expected level %q, got %v", tt.wantLevel, got)
+			}
+
+			if got := entry["code"]; got != tt.wantCode {
+				t.Errorf("expected code %q, got %v", tt.wantCode, got)
+			}
+
+			if got := entry["method"]; got != info.FullMethod {
+				t.Errorf("expected method %q, got %v", info.FullMethod, got)
+			}
+		})
+	}
+}
+
+type testServerStream struct {
+	grpc.ServerStream
+}
+
+func (s *testServerStream) Context() context.Context {
+	return context.Background()
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	l, buf := newTestLogger(t)
+	info := &grpc.StreamServerInfo{FullMethod: "/test.Service/Stream"}
+
+	var handlerLogger log.ILogger
+	err := StreamServerInterceptor(l)(nil, &testServerStream{}, info, func(_ any, stream grpc.ServerStream) error {
+		handlerLogger = log.FromContext(stream.Context())
+
+		return status.Error(codes.Internal, "broken")
+	})
+	if status.Code(err) != codes.Internal {
+		t.Errorf("expected internal error, got %v", err)
+	}
+
+	if handlerLogger != log.ILogger(l) {
+		t.Error("expected the stream context to hold the request-scoped logger")
+	}
+
+	entry := decode(t, buf)
+	if got := entry["severity"]; got != "error" {
+		t.Errorf("expected level error, got %v", got)
+	}
+
+	if got := entry["code"]; got != "Internal" {
+		t.Errorf("expected code Internal, got %v", got)
+	}
+}
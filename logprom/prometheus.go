@@ -0,0 +1,59 @@
+// Package logprom exports the log statistics of the log package as
+// Prometheus metrics. It is a separate module, so that the log package
+// itself does not depend on the Prometheus client.
+package logprom
+
+import (
+	stderrors "errors"
+
+	"github.com/Rapix-x/log"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var loggedDesc = prometheus.NewDesc(
+	"log_statements_total",
+	"Number of log statements written per level.",
+	[]string{"level"},
+	nil,
+)
+
+// RegisterPrometheus registers a counter of the log statements written
+// by the logger and all loggers derived from it, labeled by level, with
+// the registerer. The counter is read from the statistics of the logger
+// on collection, so logging does not pay for it. If the logger has
+// already been registered with the registerer, nothing happens. As the
+// metric has no label for the logger, registering a different logger
+// results in an error; use prometheus.WrapRegistererWith to register
+// several loggers with distinguishing labels.
+func RegisterPrometheus(registerer prometheus.Registerer, l *log.Logger) error {
+	if registerer == nil {
+		return errors.New("invalid nil registerer")
+	}
+
+	err := registerer.Register(&collector{logger: l})
+
+	var alreadyRegistered prometheus.AlreadyRegisteredError
+	if stderrors.As(err, &alreadyRegistered) {
+		if existing, ok := alreadyRegistered.ExistingCollector.(*collector); ok && existing.logger == l {
+			return nil
+		}
+	}
+
+	return err
+}
+
+// The collector reports the per-level statistics of the logger.
+type collector struct {
+	logger *log.Logger
+}
+
+func (c *collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- loggedDesc
+}
+
+func (c *collector) Collect(ch chan<- prometheus.Metric) {
+	for level, count := range c.logger.LevelStats() {
+		ch <- prometheus.MustNewConstMetric(loggedDesc, prometheus.CounterValue, float64(count), level.String())
+	}
+}
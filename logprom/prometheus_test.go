@@ -0,0 +1,64 @@
+package logprom
+
+import (
+	"io"
+	"testing"
+
+	"github.com/Rapix-x/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func newTestLogger(t *testing.T) *log.Logger {
+	t.Helper()
+
+	l, err := log.NewLogger(log.Configuration{MinimumLogLevel: log.DebugLevel, Output: io.Discard})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return l
+}
+
+func TestRegisterPrometheus(t *testing.T) {
+	l := newTestLogger(t)
+	reg := prometheus.NewRegistry()
+
+	if err := RegisterPrometheus(reg, l); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	l.Info("info")
+	l.Warn("warn")
+	l.Warn("warn")
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	counts := make(map[string]float64)
+	for _, m := range families[0].GetMetric() {
+		counts[m.GetLabel()[0].GetValue()] = m.GetCounter().GetValue()
+	}
+
+	if counts["info"] != 1 || counts["warn"] != 2 || counts["error"] != 0 {
+		t.Errorf("unexpected counts: %v", counts)
+	}
+}
+
+func TestRegisterPrometheusTwice(t *testing.T) {
+	l := newTestLogger(t)
+	reg := prometheus.NewRegistry()
+
+	if err := RegisterPrometheus(reg, l); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := RegisterPrometheus(reg, l); err != nil {
+		t.Errorf("expected registering the same logger again to be a no-op, got %v", err)
+	}
+
+	if err := RegisterPrometheus(reg, newTestLogger(t)); err == nil {
+		t.Error("expected an error for registering a different logger")
+	}
+}
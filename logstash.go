@@ -0,0 +1,35 @@
+package log
+
+import "go.uber.org/zap/zapcore"
+
+const (
+	logstashVersionKey = "@version"
+	logstashVersion    = "1"
+)
+
+var logstashEncoderConfig = zapcore.EncoderConfig{
+	MessageKey:          "message",
+	LevelKey:            "level",
+	TimeKey:             "@timestamp",
+	NameKey:             "logger_name",
+	CallerKey:           "caller",
+	FunctionKey:         "func",
+	StacktraceKey:       "stack_trace",
+	SkipLineEnding:      false,
+	LineEnding:          "\n",
+	EncodeLevel:         zapcore.CapitalLevelEncoder,
+	EncodeTime:          zapcore.RFC3339TimeEncoder,
+	EncodeDuration:      zapcore.MillisDurationEncoder,
+	EncodeCaller:        zapcore.ShortCallerEncoder,
+	EncodeName:          nil,
+	NewReflectedEncoder: nil,
+}
+
+// newLogstashEncoder creates a JSON encoder, which adds the static
+// "@version" field expected by the json codec of Logstash.
+func newLogstashEncoder(conf zapcore.EncoderConfig) zapcore.Encoder {
+	encoder := zapcore.NewJSONEncoder(conf)
+	encoder.AddString(logstashVersionKey, logstashVersion)
+
+	return encoder
+}
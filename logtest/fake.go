@@ -0,0 +1,456 @@
+// Package logtest provides helpers for testing code that depends on the
+// log package.
+package logtest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Rapix-x/log"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Call represents a single recorded call to a FakeLogger.
+type Call struct {
+	// Level is the level the call has been logged on.
+	Level log.Level
+
+	// Format holds the format string of f-methods and the message of
+	// w-methods. It is empty for the plain methods.
+	Format string
+
+	// Message is the final, formatted message of the call.
+	Message string
+
+	// Args holds the raw arguments of the call. For w-methods these are
+	// the key-value pairs including the ones added via With.
+	Args []any
+}
+
+// The FakeLogger implements log.ILogger and records every call instead
+// of writing any logs. The zero value is ready to use. It is safe for
+// concurrent use; the recorded calls are read via Recorded.
+type FakeLogger struct {
+	mu    sync.Mutex
+	calls []Call
+
+	// The root holds the calls of all loggers derived via With. It is nil
+	// for the root itself.
+	root   *FakeLogger
+	fields []any
+}
+
+var _ log.ILogger = (*FakeLogger)(nil)
+
+// NewFakeLogger creates a new FakeLogger.
+func NewFakeLogger() *FakeLogger {
+	return &FakeLogger{}
+}
+
+// recorder returns the logger holding the recorded calls.
+func (l *FakeLogger) recorder() *FakeLogger {
+	if l.root != nil {
+		return l.root
+	}
+
+	return l
+}
+
+// Contains reports whether a call on the given level has been recorded,
+// whose message contains substr.
+func (l *FakeLogger) Contains(level log.Level, substr string) bool {
+	for _, c := range l.Recorded() {
+		if c.Level == level && strings.Contains(c.Message, substr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Recorded returns a copy of all calls recorded so far by the logger and
+// all loggers derived from it via With.
+func (l *FakeLogger) Recorded() []Call {
+	r := l.recorder()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	out := make([]Call, len(r.calls))
+	copy(out, r.calls)
+
+	return out
+}
+
+// Reset removes all recorded calls.
+func (l *FakeLogger) Reset() {
+	r := l.recorder()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.calls = nil
+}
+
+func (l *FakeLogger) Debug(v ...any) {
+	l.record(log.DebugLevel, "", fmt.Sprint(v...), v)
+}
+
+func (l *FakeLogger) Debugf(format string, v ...any) {
+	l.record(log.DebugLevel, format, fmt.Sprintf(format, v...), v)
+}
+
+func (l *FakeLogger) Debugln(v ...any) {
+	l.record(log.DebugLevel, "", sprintln(v), v)
+}
+
+func (l *FakeLogger) Debugw(msg string, keyValuePairs ...any) {
+	l.recordw(log.DebugLevel, msg, keyValuePairs)
+}
+
+func (l *FakeLogger) DPanic(v ...any) {
+	l.record(log.DPanicLevel, "", fmt.Sprint(v...), v)
+}
+
+func (l *FakeLogger) DPanicf(format string, v ...any) {
+	l.record(log.DPanicLevel, format, fmt.Sprintf(format, v...), v)
+}
+
+func (l *FakeLogger) DPanicln(v ...any) {
+	l.record(log.DPanicLevel, "", sprintln(v), v)
+}
+
+func (l *FakeLogger) DPanicw(msg string, keyValuePairs ...any) {
+	l.recordw(log.DPanicLevel, msg, keyValuePairs)
+}
+
+func (l *FakeLogger) Error(v ...any) {
+	l.record(log.ErrorLevel, "", fmt.Sprint(v...), v)
+}
+
+func (l *FakeLogger) Errorf(format string, v ...any) {
+	l.record(log.ErrorLevel, format, fmt.Sprintf(format, v...), v)
+}
+
+func (l *FakeLogger) Errorln(v ...any) {
+	l.record(log.ErrorLevel, "", sprintln(v), v)
+}
+
+func (l *FakeLogger) Errorw(msg string, keyValuePairs ...any) {
+	l.recordw(log.ErrorLevel, msg, keyValuePairs)
+}
+
+// Fatal records the call, but does not exit.
+func (l *FakeLogger) Fatal(v ...any) {
+	l.record(log.FatalLevel, "", fmt.Sprint(v...), v)
+}
+
+// Fatalf records the call, but does not exit.
+func (l *FakeLogger) Fatalf(format string, v ...any) {
+	l.record(log.FatalLevel, format, fmt.Sprintf(format, v...), v)
+}
+
+// Fatalln records the call, but does not exit.
+func (l *FakeLogger) Fatalln(v ...any) {
+	l.record(log.FatalLevel, "", sprintln(v), v)
+}
+
+// Fatalw records the call, but does not exit.
+func (l *FakeLogger) Fatalw(msg string, keyValuePairs ...any) {
+	l.recordw(log.FatalLevel, msg, keyValuePairs)
+}
+
+func (l *FakeLogger) Info(v ...any) {
+	l.record(log.InfoLevel, "", fmt.Sprint(v...), v)
+}
+
+func (l *FakeLogger) Infof(format string, v ...any) {
+	l.record(log.InfoLevel, format, fmt.Sprintf(format, v...), v)
+}
+
+func (l *FakeLogger) Infoln(v ...any) {
+	l.record(log.InfoLevel, "", sprintln(v), v)
+}
+
+func (l *FakeLogger) Infow(msg string, keyValuePairs ...any) {
+	l.recordw(log.InfoLevel, msg, keyValuePairs)
+}
+
+// Log records the call on the given level or on the info level, if the
+// level is invalid. On the panic level, it panics afterwards.
+func (l *FakeLogger) Log(level log.Level, v ...any) {
+	if level == log.PanicLevel {
+		l.Panic(v...)
+	}
+
+	l.record(validLevel(level), "", fmt.Sprint(v...), v)
+}
+
+// Logf records the call on the given level or on the info level, if the
+// level is invalid. On the panic level, it panics afterwards.
+func (l *FakeLogger) Logf(level log.Level, format string, v ...any) {
+	if level == log.PanicLevel {
+		l.Panicf(format, v...)
+	}
+
+	l.record(validLevel(level), format, fmt.Sprintf(format, v...), v)
+}
+
+// Logw records the call on the given level or on the info level, if the
+// level is invalid. On the panic level, it panics afterwards.
+func (l *FakeLogger) Logw(level log.Level, msg string, keyValuePairs ...any) {
+	if level == log.PanicLevel {
+		l.Panicw(msg, keyValuePairs...)
+	}
+
+	l.recordw(validLevel(level), msg, keyValuePairs)
+}
+
+// Panic records the call and panics with the message afterwards.
+func (l *FakeLogger) Panic(v ...any) {
+	msg := fmt.Sprint(v...)
+	l.record(log.PanicLevel, "", msg, v)
+	panic(msg)
+}
+
+// Panicf records the call and panics with the message afterwards.
+func (l *FakeLogger) Panicf(format string, v ...any) {
+	msg := fmt.Sprintf(format, v...)
+	l.record(log.PanicLevel, format, msg, v)
+	panic(msg)
+}
+
+// Panicln records the call and panics with the message afterwards.
+func (l *FakeLogger) Panicln(v ...any) {
+	msg := sprintln(v)
+	l.record(log.PanicLevel, "", msg, v)
+	panic(msg)
+}
+
+// Panicw records the call and panics with the message afterwards.
+func (l *FakeLogger) Panicw(msg string, keyValuePairs ...any) {
+	l.recordw(log.PanicLevel, msg, keyValuePairs)
+	panic(msg)
+}
+
+// RecoverAndLog recovers from a panic and records it on the error level.
+// It never re-panics.
+func (l *FakeLogger) RecoverAndLog(msg string) {
+	if r := recover(); r != nil {
+		l.recordw(log.ErrorLevel, msg, []any{"panic", r})
+	}
+}
+
+// RecoverAndRePanic recovers from a panic, records it on the error level
+// and panics again with the recovered value.
+func (l *FakeLogger) RecoverAndRePanic(msg string) {
+	if r := recover(); r != nil {
+		l.recordw(log.ErrorLevel, msg, []any{"panic", r})
+		panic(r)
+	}
+}
+
+// Sync is a no-op.
+func (l *FakeLogger) Sync() error {
+	return nil
+}
+
+func (l *FakeLogger) Warn(v ...any) {
+	l.record(log.WarnLevel, "", fmt.Sprint(v...), v)
+}
+
+func (l *FakeLogger) Warnf(format string, v ...any) {
+	l.record(log.WarnLevel, format, fmt.Sprintf(format, v...), v)
+}
+
+func (l *FakeLogger) Warnln(v ...any) {
+	l.record(log.WarnLevel, "", sprintln(v), v)
+}
+
+func (l *FakeLogger) Warnw(msg string, keyValuePairs ...any) {
+	l.recordw(log.WarnLevel, msg, keyValuePairs)
+}
+
+// With returns a new FakeLogger, which records its calls together with
+// the ones of its parent and adds the given fields to every w-call.
+func (l *FakeLogger) With(keyValuePairs ...any) log.ILogger {
+	fields := make([]any, 0, len(l.fields)+len(keyValuePairs))
+	fields = append(fields, l.fields...)
+	fields = append(fields, keyValuePairs...)
+
+	return &FakeLogger{
+		root:   l.recorder(),
+		fields: fields,
+	}
+}
+
+// WithError returns a new FakeLogger, which adds the error under the
+// "error" key to every w-call. If the error is nil, the logger is
+// returned as is.
+func (l *FakeLogger) WithError(err error) log.ILogger {
+	if err == nil {
+		return l
+	}
+
+	return l.With("error", err)
+}
+
+// WithFields returns a new FakeLogger, which adds the given fields in the
+// order of their keys to every w-call.
+func (l *FakeLogger) WithFields(fields map[string]any) log.ILogger {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	keyValuePairs := make([]any, 0, 2*len(keys))
+	for _, key := range keys {
+		keyValuePairs = append(keyValuePairs, key, fields[key])
+	}
+
+	return l.With(keyValuePairs...)
+}
+
+// WithLazy works like With, as the FakeLogger does not materialize any
+// fields.
+func (l *FakeLogger) WithLazy(keyValuePairs ...any) log.ILogger {
+	return l.With(keyValuePairs...)
+}
+
+// Namespace returns a new FakeLogger, which adds a zap.Namespace field
+// with the given name to every w-call.
+func (l *FakeLogger) Namespace(name string) log.ILogger {
+	return l.With(zap.Namespace(name))
+}
+
+// WithContext returns the logger as is, as the FakeLogger has no
+// context extractors.
+func (l *FakeLogger) WithContext(ctx context.Context) log.ILogger {
+	return l
+}
+
+// WithLevel returns the logger as is, as the FakeLogger records calls on
+// all levels.
+func (l *FakeLogger) WithLevel(level log.Level) log.ILogger {
+	return l
+}
+
+// WithPIIMode returns the logger as is, as the FakeLogger records PII
+// fields unresolved.
+func (l *FakeLogger) WithPIIMode(mode log.PIIMode) log.ILogger {
+	return l
+}
+
+// Tee returns the logger as is, as the FakeLogger does not write to any
+// core.
+func (l *FakeLogger) Tee(extra zapcore.Core) log.ILogger {
+	return l
+}
+
+// Merge returns a new FakeLogger, which adds the fields of the other
+// logger after its own ones to every w-call. The other logger can be a
+// FakeLogger or a log.Logger.
+func (l *FakeLogger) Merge(other log.ILogger) log.ILogger {
+	switch o := other.(type) {
+	case *FakeLogger:
+		return l.With(o.fields...)
+	case *log.Logger:
+		fields := o.Fields()
+
+		keyValuePairs := make([]any, len(fields))
+		for i, f := range fields {
+			keyValuePairs[i] = f
+		}
+
+		return l.With(keyValuePairs...)
+	default:
+		return l
+	}
+}
+
+// Without returns a new FakeLogger without the fields with the given
+// keys, which have been added via With and similar methods.
+func (l *FakeLogger) Without(keys ...string) log.ILogger {
+	removed := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		removed[key] = struct{}{}
+	}
+
+	fields := make([]any, 0, len(l.fields))
+
+	for i := 0; i < len(l.fields); i++ {
+		switch element := l.fields[i].(type) {
+		case zap.Field:
+			if _, ok := removed[element.Key]; !ok {
+				fields = append(fields, element)
+			}
+		case string:
+			if i == len(l.fields)-1 {
+				fields = append(fields, element)
+
+				continue
+			}
+
+			if _, ok := removed[element]; !ok {
+				fields = append(fields, element, l.fields[i+1])
+			}
+
+			i++
+		default:
+			fields = append(fields, element)
+		}
+	}
+
+	return &FakeLogger{
+		root:   l.recorder(),
+		fields: fields,
+	}
+}
+
+func (l *FakeLogger) recordw(level log.Level, msg string, keyValuePairs []any) {
+	args := make([]any, 0, len(l.fields)+len(keyValuePairs))
+	args = append(args, l.fields...)
+	args = append(args, keyValuePairs...)
+
+	l.record(level, msg, msg, args)
+}
+
+func (l *FakeLogger) record(level log.Level, format, msg string, args []any) {
+	r := l.recorder()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.calls = append(r.calls, Call{
+		Level:   level,
+		Format:  format,
+		Message: msg,
+		Args:    args,
+	})
+}
+
+// sprintln formats the values like fmt.Sprintln without the trailing
+// newline.
+func sprintln(v []any) string {
+	msg := fmt.Sprintln(v...)
+
+	return msg[:len(msg)-1]
+}
+
+// validLevel returns the level, if it is valid, and the info level
+// otherwise, like the dynamic-level methods of the logger.
+func validLevel(level log.Level) log.Level {
+	switch level {
+	case log.DebugLevel, log.InfoLevel, log.WarnLevel, log.ErrorLevel,
+		log.DPanicLevel, log.PanicLevel, log.FatalLevel:
+		return level
+	default:
+		return log.InfoLevel
+	}
+}
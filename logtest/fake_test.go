@@ -0,0 +1,109 @@
+package logtest
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/Rapix-x/log"
+)
+
+func TestFakeLoggerZeroValue(t *testing.T) {
+	var l FakeLogger
+
+	l.Infow("zero value", "k", "v")
+	l.With("req", 1).Errorf("failed: %d", 42)
+
+	calls := l.Recorded()
+	if len(calls) != 2 {
+		t.Fatalf("expected 2 calls, got %d", len(calls))
+	}
+
+	if !l.Contains(log.ErrorLevel, "failed: 42") {
+		t.Error("expected the call of the derived logger to be recorded")
+	}
+
+	l.Reset()
+
+	if got := len(l.Recorded()); got != 0 {
+		t.Errorf("expected no calls after reset, got %d", got)
+	}
+}
+
+func TestFakeLoggerConcurrentUse(t *testing.T) {
+	l := NewFakeLogger()
+	child := l.With("worker", true)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+
+		go func(i int) {
+			defer wg.Done()
+
+			l.Infow("parent", "i", i)
+			_ = l.Recorded()
+		}(i)
+
+		go func(i int) {
+			defer wg.Done()
+
+			child.Warnw("child", "i", i)
+			_ = child.(*FakeLogger).Contains(log.WarnLevel, "child")
+		}(i)
+	}
+
+	wg.Wait()
+
+	if got := len(l.Recorded()); got != 20 {
+		t.Errorf("expected 20 calls, got %d", got)
+	}
+}
+
+func TestFakeLoggerWithFamily(t *testing.T) {
+	l := NewFakeLogger()
+	err := errors.New("boom")
+
+	l.WithError(err).WithFields(map[string]any{"b": 2, "a": 1}).WithLazy("lazy", true).Infow("chained", "k", "v")
+
+	calls := l.Recorded()
+	if len(calls) != 1 {
+		t.Fatalf("expected 1 call, got %d", len(calls))
+	}
+
+	want := []any{"error", err, "a", 1, "b", 2, "lazy", true, "k", "v"}
+	if !reflect.DeepEqual(calls[0].Args, want) {
+		t.Errorf("expected args %v, got %v", want, calls[0].Args)
+	}
+
+	if got := l.WithError(nil); got != log.ILogger(l) {
+		t.Error("expected the logger to be returned as is for a nil error")
+	}
+}
+
+func TestFakeLoggerDerivation(t *testing.T) {
+	l := NewFakeLogger()
+	other := NewFakeLogger().With("merged", 1)
+
+	l.With("k", "v", "drop", true).
+		WithContext(context.Background()).
+		WithLevel(log.DebugLevel).
+		WithPIIMode(log.PIIModeHash).
+		Without("drop").
+		Merge(other).
+		Tee(nil).
+		Infow("chained")
+
+	calls := l.Recorded()
+	if len(calls) != 1 {
+		t.Fatalf("expected 1 call, got %d", len(calls))
+	}
+
+	want := []any{"k", "v", "merged", 1}
+	if !reflect.DeepEqual(calls[0].Args, want) {
+		t.Errorf("expected args %v, got %v", want, calls[0].Args)
+	}
+}
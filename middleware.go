@@ -0,0 +1,188 @@
+package log
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// piiQueryKeys holds the query parameters, which are known to carry PII
+// and are therefore logged as PII fields by HTTPRequestFields.
+var piiQueryKeys = map[string]struct{}{
+	"email":         {},
+	"mail":          {},
+	"phone":         {},
+	"name":          {},
+	"first_name":    {},
+	"last_name":     {},
+	"address":       {},
+	"ssn":           {},
+	"password":      {},
+	"token":         {},
+	"access_token":  {},
+	"refresh_token": {},
+	"api_key":       {},
+	"apikey":        {},
+	"secret":        {},
+}
+
+// HTTPRequestFields returns the standard key-value pairs of an access
+// log, i.e. method, path, status, written bytes, duration, remote
+// address and user agent, ready to be passed to the w-methods. Query
+// parameters are added with the "query." prefix, where the values of
+// parameters known to carry PII, e.g. "email" or "token", are added as
+// PII fields, so they are resolved according to the PII mode.
+func HTTPRequestFields(r *http.Request, status int, bytes int, d time.Duration) []any {
+	keyValuePairs := []any{
+		"method", r.Method,
+		"path", r.URL.Path,
+		"status", status,
+		"bytes", bytes,
+		DurationField("duration", d),
+		"remote_addr", r.RemoteAddr,
+		"user_agent", r.UserAgent(),
+	}
+
+	query := r.URL.Query()
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := strings.Join(query[key], ",")
+
+		if _, ok := piiQueryKeys[strings.ToLower(key)]; ok {
+			keyValuePairs = append(keyValuePairs, PII("query."+key, value))
+
+			continue
+		}
+
+		keyValuePairs = append(keyValuePairs, "query."+key, value)
+	}
+
+	return keyValuePairs
+}
+
+// A MiddlewareConfiguration configures the HTTP middleware created via
+// Middleware.
+type MiddlewareConfiguration struct {
+	// SkipPaths lists the request paths, which are not logged, e.g.
+	// health checks like "/healthz". The request-scoped logger is still
+	// stored in the request context.
+	SkipPaths []string
+}
+
+// Middleware creates an HTTP middleware, which logs every request via
+// HTTPRequestFields once it has been handled. Requests with a server
+// error status are logged on the error level, all others on the info
+// level. The middleware stores a request-scoped logger containing the
+// fields extracted by the ContextExtractors in the request context,
+// which can be retrieved via FromContext.
+func Middleware(l *Logger, conf MiddlewareConfiguration) func(http.Handler) http.Handler {
+	l = handleUninitialized(l)
+
+	skipPaths := make(map[string]struct{}, len(conf.SkipPaths))
+	for _, path := range conf.SkipPaths {
+		skipPaths[path] = struct{}{}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			requestLogger := l.WithContext(r.Context())
+			r = r.WithContext(ContextWithLogger(r.Context(), requestLogger))
+
+			rw := &responseWriter{ResponseWriter: w}
+			next.ServeHTTP(rw, r)
+
+			if _, ok := skipPaths[r.URL.Path]; ok {
+				return
+			}
+
+			level := InfoLevel
+			if rw.status() >= http.StatusInternalServerError {
+				level = ErrorLevel
+			}
+
+			requestLogger.Logw(level, "handled request", HTTPRequestFields(r, rw.status(), rw.bytes, time.Since(start))...)
+		})
+	}
+}
+
+// The responseWriter captures the status and the number of written
+// bytes of a response.
+type responseWriter struct {
+	http.ResponseWriter
+	statusCode int
+	bytes      int
+}
+
+func (w *responseWriter) WriteHeader(statusCode int) {
+	if w.statusCode == 0 {
+		w.statusCode = statusCode
+	}
+
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	if w.statusCode == 0 {
+		w.statusCode = http.StatusOK
+	}
+
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += n
+
+	return n, err
+}
+
+// Flush forwards to the original response writer, if it supports
+// flushing, e.g. for server-sent events.
+func (w *responseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		if w.statusCode == 0 {
+			w.statusCode = http.StatusOK
+		}
+
+		f.Flush()
+	}
+}
+
+// Hijack forwards to the original response writer, if it supports
+// hijacking, e.g. for websocket upgrades.
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response writer does not support hijacking")
+	}
+
+	conn, rw, err := h.Hijack()
+	if err == nil && w.statusCode == 0 {
+		w.statusCode = http.StatusSwitchingProtocols
+	}
+
+	return conn, rw, err
+}
+
+// Unwrap returns the original response writer, so that it can be used
+// via http.ResponseController.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *responseWriter) status() int {
+	if w.statusCode == 0 {
+		return http.StatusOK
+	}
+
+	return w.statusCode
+}
@@ -0,0 +1,88 @@
+package log
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestMiddlewareForwardsFlush(t *testing.T) {
+	l, buf := newTestLogger(t, Configuration{})
+
+	handler := Middleware(l, MiddlewareConfiguration{})(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		f, ok := w.(http.Flusher)
+		if !ok {
+			t.Fatal("expected the response writer to implement http.Flusher")
+		}
+
+		_, _ = w.Write([]byte("event"))
+		f.Flush()
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/events", nil))
+
+	if !rec.Flushed {
+		t.Error("expected the flush to be forwarded")
+	}
+
+	if lines := buf.lines(t); len(lines) != 1 {
+		t.Errorf("expected 1 log line, got %d", len(lines))
+	}
+}
+
+func TestMiddlewareForwardsHijack(t *testing.T) {
+	l, buf := newTestLogger(t, Configuration{})
+
+	handler := Middleware(l, MiddlewareConfiguration{})(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		h, ok := w.(http.Hijacker)
+		if !ok {
+			t.Fatal("expected the response writer to implement http.Hijacker")
+		}
+
+		conn, _, err := h.Hijack()
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+
+			return
+		}
+
+		_ = conn.Close()
+	}))
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	if resp, err := http.Get(server.URL + "/ws"); err == nil {
+		_ = resp.Body.Close()
+	}
+
+	// The request is logged by the server after the connection has been
+	// closed, which might happen after the client has returned.
+	deadline := time.Now().Add(time.Second)
+	for buf.String() == "" && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	lines := buf.lines(t)
+	if len(lines) != 1 {
+		t.Fatalf("expected 1 log line, got %d: %s", len(lines), buf.String())
+	}
+
+	if got := lines[0]["status"]; got != float64(http.StatusSwitchingProtocols) {
+		t.Errorf("expected status 101 for the hijacked connection, got %v", got)
+	}
+}
+
+func TestMiddlewareHijackUnsupported(t *testing.T) {
+	l, _ := newTestLogger(t, Configuration{})
+
+	handler := Middleware(l, MiddlewareConfiguration{})(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if _, _, err := w.(http.Hijacker).Hijack(); err == nil {
+			t.Error("expected an error for a response writer without hijacking support")
+		}
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
@@ -0,0 +1,34 @@
+package log
+
+import (
+	"context"
+	"encoding/hex"
+)
+
+// OpenTelemetrySpanFunc returns the trace and span ID of the active
+// OpenTelemetry span held by the context. If the context does not hold
+// a valid span context, ok shall be false.
+type OpenTelemetrySpanFunc func(ctx context.Context) (traceID [16]byte, spanID [8]byte, ok bool)
+
+// NewOpenTelemetryContextExtractor creates a ContextExtractor, which
+// adds the hex encoded IDs of the active span as "trace_id" and
+// "span_id" to correlate logs and traces. As this package does not
+// depend on OpenTelemetry, the IDs are obtained via spanFunc, e.g.
+//
+//	func(ctx context.Context) ([16]byte, [8]byte, bool) {
+//		sc := trace.SpanContextFromContext(ctx)
+//		return sc.TraceID(), sc.SpanID(), sc.IsValid()
+//	}
+func NewOpenTelemetryContextExtractor(spanFunc OpenTelemetrySpanFunc) ContextExtractor {
+	return func(ctx context.Context) []any {
+		traceID, spanID, ok := spanFunc(ctx)
+		if !ok {
+			return nil
+		}
+
+		return []any{
+			"trace_id", hex.EncodeToString(traceID[:]),
+			"span_id", hex.EncodeToString(spanID[:]),
+		}
+	}
+}
@@ -1,10 +1,22 @@
 package log
 
 import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
 	"crypto/sha256"
+	"encoding/base64"
 	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
 
+	"github.com/pkg/errors"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 // PIIMode indicates how to resolve PII fields in log statements.
@@ -20,49 +32,224 @@ const (
 
 	// PIIModeMask indicates that the value part of a PII field shall
 	// be masked. If this mode is selected a mask function needs to be
-	// provided under the MaskFunc property of this package. If no
-	// MaskFunc is provided, PII fields will be omitted in the logs
-	// using this mode.
+	// provided via SetMaskFunc. If no mask function is provided, the
+	// values of PII fields will be replaced by MaskFuncMissingPlaceholder
+	// in the logs using this mode.
 	PIIModeMask PIIMode = 2
 
 	// PIIModeRemove indicates that PII fields shall be omitted
 	// completely from the final logs.
 	PIIModeRemove PIIMode = 3
+
+	// PIIModeEncrypt indicates that the value part of a PII field shall
+	// be encrypted (AES-GCM) with the PIIEncryptionKey of the logger
+	// configuration. The value is logged as the base64 encoded nonce
+	// followed by the ciphertext and can be recovered via DecryptPII.
+	// Without the key, the logged value is meaningless.
+	PIIModeEncrypt PIIMode = 4
 )
 
 var (
 	piiModes = map[PIIMode]struct{}{
-		PIIModeNone:   {},
-		PIIModeHash:   {},
-		PIIModeMask:   {},
-		PIIModeRemove: {},
+		PIIModeNone:    {},
+		PIIModeHash:    {},
+		PIIModeMask:    {},
+		PIIModeRemove:  {},
+		PIIModeEncrypt: {},
+	}
+
+	piiModeNames = map[PIIMode]string{
+		PIIModeNone:    "none",
+		PIIModeHash:    "hash",
+		PIIModeMask:    "mask",
+		PIIModeRemove:  "remove",
+		PIIModeEncrypt: "encrypt",
 	}
 
 	// MaskFunc gets called on PII resolvers, when PII mode "mask" is chosen.
 	// The function shall be thread-safe. When no function is provided, but
-	// the mask PII mode is chosen, any PII fields will be omitted.
+	// the mask PII mode is chosen, the values of any PII fields will be
+	// replaced by MaskFuncMissingPlaceholder. A function set via
+	// SetMaskFunc takes precedence over this one.
+	//
+	// Deprecated: Use SetMaskFunc instead, as assigning MaskFunc while
+	// loggers are in use is a data race.
 	MaskFunc func(key, value string) ResolvedPIIField
+
+	maskFunc atomic.Value
 )
 
+type maskFuncHolder struct {
+	f func(key, value string) ResolvedPIIField
+}
+
+// SetMaskFunc sets the function that gets called on PII resolvers, when
+// PII mode "mask" is chosen. It is safe to call SetMaskFunc while loggers
+// are in use. The function itself shall be thread-safe. Passing nil
+// unsets a previously set function.
+func SetMaskFunc(f func(key, value string) ResolvedPIIField) {
+	maskFunc.Store(maskFuncHolder{f: f})
+}
+
+func loadMaskFunc() func(key, value string) ResolvedPIIField {
+	if h, ok := maskFunc.Load().(maskFuncHolder); ok && h.f != nil {
+		return h.f
+	}
+
+	return MaskFunc
+}
+
+// MaskFuncMissingPlaceholder is logged instead of the value of a PII
+// field, when PII mode "mask" is chosen, but no mask function is provided.
+// This makes the misconfiguration visible without leaking any PII.
+const MaskFuncMissingPlaceholder = "***MASK_FUNC_MISSING***"
+
+// MaskReveal creates a mask function, which keeps the given number of
+// leading and trailing characters of the value visible and replaces all
+// others by the mask character, e.g. MaskReveal(0, 4, '*') masks an
+// account number except for its last four digits. If the visible
+// characters would cover the whole value, all characters are masked.
+// The function can be set via SetMaskFunc or used within a
+// CustomResolveFunc.
+func MaskReveal(leadingVisible, trailingVisible int, maskChar rune) func(key, value string) ResolvedPIIField {
+	if leadingVisible < 0 {
+		leadingVisible = 0
+	}
+
+	if trailingVisible < 0 {
+		trailingVisible = 0
+	}
+
+	return func(key, value string) ResolvedPIIField {
+		runes := []rune(value)
+
+		leading, trailing := leadingVisible, trailingVisible
+		if leading+trailing >= len(runes) {
+			leading, trailing = 0, 0
+		}
+
+		for i := leading; i < len(runes)-trailing; i++ {
+			runes[i] = maskChar
+		}
+
+		return ResolvedPIIField{Key: key, Value: string(runes)}
+	}
+}
+
+// String returns the name of the PII mode, e.g. "hash".
+func (m PIIMode) String() string {
+	if name, ok := piiModeNames[m]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("PIIMode(%d)", m)
+}
+
+// ParsePIIMode parses a PII mode from its name, e.g. "hash" or "MASK".
+// An error is returned for unknown PII mode names.
+func ParsePIIMode(text string) (PIIMode, error) {
+	for mode, name := range piiModeNames {
+		if _, ok := piiModes[mode]; ok && strings.EqualFold(name, text) {
+			return mode, nil
+		}
+	}
+
+	return PIIModeNone, errors.Errorf("unknown PII mode %q", text)
+}
+
+// MarshalText encodes the PII mode as its name, e.g. "hash", so that PII
+// modes can be stored in configuration files.
+func (m PIIMode) MarshalText() ([]byte, error) {
+	if _, ok := piiModes[m]; !ok {
+		return nil, errors.Errorf("invalid PII mode %d", m)
+	}
+
+	return []byte(m.String()), nil
+}
+
+// UnmarshalText decodes the PII mode from its name via ParsePIIMode.
+func (m *PIIMode) UnmarshalText(text []byte) error {
+	mode, err := ParsePIIMode(string(text))
+	if err != nil {
+		return err
+	}
+
+	*m = mode
+
+	return nil
+}
+
+// MarshalJSON encodes the PII mode as a JSON string holding its name.
+func (m PIIMode) MarshalJSON() ([]byte, error) {
+	text, err := m.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(string(text))
+}
+
+// UnmarshalJSON decodes the PII mode from a JSON string holding its name.
+func (m *PIIMode) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return errors.Wrap(err, "invalid PII mode")
+	}
+
+	return m.UnmarshalText([]byte(text))
+}
+
+// WithPIIMode returns a new logger, which resolves PII fields in its log
+// statements according to the given PII mode, e.g. to remove PII from a
+// single log statement with a broad audience:
+//
+//	logger.WithPIIMode(log.PIIModeRemove).Errorw("payment failed", log.PII("email", email))
+//
+// PIIModeEncrypt requires the logger to be configured with an encryption
+// key; otherwise, PII fields are skipped.
+func (l *Logger) WithPIIMode(mode PIIMode) ILogger {
+	l = handleUninitialized(l)
+
+	out := l.withSugaredLogger(l.logger)
+	out.piiMode = mode
+
+	return out
+}
+
 type field struct {
 	key   string
 	value string
 }
 
-func (f *field) resolve(piiMode PIIMode) zap.Field {
+// Resolve resolves the field according to the PII mode. Outside of a
+// logger, no encryption key is available, so the field is skipped in
+// PIIModeEncrypt.
+func (f *field) Resolve(piiMode PIIMode) zap.Field {
+	return f.resolve(piiMode, nil)
+}
+
+func (f *field) resolve(piiMode PIIMode, piiCipher cipher.AEAD) zap.Field {
 	switch piiMode {
 	case PIIModeNone:
 		return zap.String(f.key, f.value)
 	case PIIModeHash:
 		return zap.String(f.key, hash(f.value))
 	case PIIModeMask:
-		if MaskFunc == nil {
-			return zap.Skip()
+		mask := loadMaskFunc()
+		if mask == nil {
+			return zap.String(f.key, MaskFuncMissingPlaceholder)
 		}
 
-		return MaskFunc(f.key, f.value).zapField()
+		return mask(f.key, f.value).zapField()
 	case PIIModeRemove:
 		return zap.Skip()
+	case PIIModeEncrypt:
+		encrypted, err := encrypt(piiCipher, f.value)
+		if err != nil {
+			return zap.Skip()
+		}
+
+		return zap.String(f.key, encrypted)
 	default:
 		return zap.Skip()
 	}
@@ -77,6 +264,27 @@ func PII(key, value string) *field {
 	}
 }
 
+// PIIField creates a typed PII field for the typed-field methods, e.g.
+// InfoFields, which is resolved according to the PII mode of the logger
+// like the fields created via PII. When logged via a zap logger
+// directly, e.g. the one returned by Desugar, the field is skipped, so
+// the PII does not leak.
+func PIIField(key, value string) Field {
+	return zap.Field{Key: key, Type: zapcore.SkipType, Interface: PII(key, value)}
+}
+
+// piiFieldResolver returns the PII field held by a field created via
+// PIIField.
+func piiFieldResolver(f zap.Field) (piiCipherResolver, bool) {
+	if f.Type != zapcore.SkipType {
+		return nil, false
+	}
+
+	e, ok := f.Interface.(piiCipherResolver)
+
+	return e, ok
+}
+
 // The CustomResolveFunc is passed to the CustomPII function of this
 // package to handle the PII resolution in a customised way before a
 // specific field gets logged.
@@ -99,13 +307,60 @@ func CustomPII(key, value string, resolveFunc CustomResolveFunc) *customPIIField
 	}
 }
 
+// piiTypes holds the resolve functions registered via RegisterPIIType
+// by the dynamic type of the values they apply to.
+var piiTypes sync.Map
+
+// RegisterPIIType registers a resolve function for all values having
+// the same dynamic type as the sample, e.g. a dedicated email type:
+//
+//	type Email string
+//
+//	log.RegisterPIIType(Email(""), resolveEmail)
+//
+// Values of a registered type are resolved according to the PII mode of
+// the logger wherever they appear as the value of a key-value pair,
+// without wrapping them via PII or CustomPII. The resolve function gets
+// the value formatted via fmt.Sprint and shall be thread-safe. Passing a
+// nil resolve function unregisters the type.
+func RegisterPIIType(sample any, resolveFunc CustomResolveFunc) {
+	if sample == nil {
+		return
+	}
+
+	t := reflect.TypeOf(sample)
+	if resolveFunc == nil {
+		piiTypes.Delete(t)
+
+		return
+	}
+
+	piiTypes.Store(t, resolveFunc)
+}
+
+// resolvePIIType resolves the value, if its type has been registered via
+// RegisterPIIType.
+func resolvePIIType(piiMode PIIMode, key string, value any) (zap.Field, bool) {
+	if value == nil {
+		return zap.Field{}, false
+	}
+
+	resolveFunc, ok := piiTypes.Load(reflect.TypeOf(value))
+	if !ok {
+		return zap.Field{}, false
+	}
+
+	return resolveFunc.(CustomResolveFunc)(piiMode, key, fmt.Sprint(value)).zapField(), true
+}
+
 type customPIIField struct {
 	key               string
 	value             string
 	customResolveFunc CustomResolveFunc
 }
 
-func (f *customPIIField) resolve(piiMode PIIMode) zap.Field {
+// Resolve resolves the field via its custom resolve function.
+func (f *customPIIField) Resolve(piiMode PIIMode) zap.Field {
 	return f.customResolveFunc(piiMode, f.key, f.value).zapField()
 }
 
@@ -123,3 +378,56 @@ func hash(in string) string {
 
 	return hex.EncodeToString(hashVal[:])
 }
+
+// newPIICipher creates the cipher used by PIIModeEncrypt. The key must
+// be 16, 24 or 32 bytes long to select AES-128, AES-192 or AES-256.
+func newPIICipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+func encrypt(piiCipher cipher.AEAD, in string) (string, error) {
+	if piiCipher == nil {
+		return "", errors.New("missing PII cipher")
+	}
+
+	nonce := make([]byte, piiCipher.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := piiCipher.Seal(nonce, nonce, []byte(in), nil)
+
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// DecryptPII recovers the value of a PII field, which has been logged
+// in PIIModeEncrypt, using the same key as the logger.
+func DecryptPII(key []byte, value string) (string, error) {
+	piiCipher, err := newPIICipher(key)
+	if err != nil {
+		return "", errors.Wrap(err, "invalid PII encryption key")
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return "", errors.Wrap(err, "invalid encrypted PII value")
+	}
+
+	if len(sealed) < piiCipher.NonceSize() {
+		return "", errors.New("invalid encrypted PII value")
+	}
+
+	nonce, ciphertext := sealed[:piiCipher.NonceSize()], sealed[piiCipher.NonceSize():]
+
+	plaintext, err := piiCipher.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", errors.Wrap(err, "could not decrypt PII value")
+	}
+
+	return string(plaintext), nil
+}
@@ -0,0 +1,59 @@
+package log
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestSetMaskFuncWhileLogging(t *testing.T) {
+	defer SetMaskFunc(nil)
+
+	l, buf := newTestLogger(t, Configuration{PIIMode: PIIModeMask})
+
+	masks := []func(key, value string) ResolvedPIIField{
+		func(key, value string) ResolvedPIIField { return ResolvedPIIField{Key: key, Value: "masked-a"} },
+		func(key, value string) ResolvedPIIField { return ResolvedPIIField{Key: key, Value: "masked-b"} },
+	}
+
+	const (
+		writers    = 4
+		iterations = 200
+	)
+
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		for i := 0; i < iterations; i++ {
+			SetMaskFunc(masks[i%len(masks)])
+		}
+	}()
+
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := 0; i < iterations; i++ {
+				l.Infow("concurrent", PII("email", "jane@example.com"))
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	lines := buf.lines(t)
+	if len(lines) != writers*iterations {
+		t.Fatalf("expected %d log lines, got %d", writers*iterations, len(lines))
+	}
+
+	for _, line := range lines {
+		email, _ := line["email"].(string)
+		if strings.Contains(email, "jane") {
+			t.Fatalf("expected masked email, got %q", email)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+const prettyJSONIndent = "  "
+
+var prettyJSONPool = buffer.NewPool()
+
+// The prettyJSONEncoder indents the JSON encoded by the wrapped encoder,
+// so that every log statement spans multiple lines.
+type prettyJSONEncoder struct {
+	zapcore.Encoder
+	lineEnding string
+}
+
+func (e *prettyJSONEncoder) Clone() zapcore.Encoder {
+	return &prettyJSONEncoder{
+		Encoder:    e.Encoder.Clone(),
+		lineEnding: e.lineEnding,
+	}
+}
+
+func (e *prettyJSONEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	buf, err := e.Encoder.EncodeEntry(ent, fields)
+	if err != nil {
+		return nil, err
+	}
+	defer buf.Free()
+
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, bytes.TrimSpace(buf.Bytes()), "", prettyJSONIndent); err != nil {
+		return nil, err
+	}
+
+	out := prettyJSONPool.Get()
+	_, _ = out.Write(indented.Bytes())
+	out.AppendString(e.lineEnding)
+
+	return out, nil
+}
@@ -0,0 +1,165 @@
+package log
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap/zapcore"
+)
+
+func validateRateLimitConf(conf Configuration) error {
+	if conf.RateLimitKey == "" {
+		return nil
+	}
+
+	if conf.RateLimitCount <= 0 {
+		return errors.New("rate limit count must be greater than 0")
+	}
+
+	if conf.RateLimitPeriod <= 0 {
+		return errors.New("rate limit period must be greater than 0")
+	}
+
+	return nil
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// The rateLimiter holds a token bucket per distinct value of the rate
+// limit key. Buckets, which have been refilled completely, are evicted
+// periodically.
+type rateLimiter struct {
+	count  int
+	period time.Duration
+	stats  *stats
+
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+func (r *rateLimiter) allow(value string, now time.Time) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	capacity := float64(r.count)
+	rate := capacity / float64(r.period)
+
+	if now.Sub(r.lastSweep) >= r.period {
+		r.lastSweep = now
+
+		for v, b := range r.buckets {
+			if b.tokens+float64(now.Sub(b.last))*rate >= capacity {
+				delete(r.buckets, v)
+			}
+		}
+	}
+
+	b, ok := r.buckets[value]
+	if !ok {
+		b = &tokenBucket{tokens: capacity, last: now}
+		r.buckets[value] = b
+	}
+
+	b.tokens += float64(now.Sub(b.last)) * rate
+	if b.tokens > capacity {
+		b.tokens = capacity
+	}
+
+	b.last = now
+
+	if b.tokens < 1 {
+		atomic.AddUint64(&r.stats.rateLimited, 1)
+
+		return false
+	}
+
+	b.tokens--
+
+	return true
+}
+
+// The rateLimitCore limits the log statements per distinct value of the
+// rate limit key, which is either added via With or on the log statement
+// itself. Log statements without the key are not limited.
+type rateLimitCore struct {
+	zapcore.Core
+	key     string
+	value   *string
+	limiter *rateLimiter
+}
+
+func newRateLimitCore(core zapcore.Core, conf Configuration, s *stats) zapcore.Core {
+	return &rateLimitCore{
+		Core: core,
+		key:  conf.RateLimitKey,
+		limiter: &rateLimiter{
+			count:   conf.RateLimitCount,
+			period:  conf.RateLimitPeriod,
+			stats:   s,
+			buckets: make(map[string]*tokenBucket),
+		},
+	}
+}
+
+func (c *rateLimitCore) With(fields []zapcore.Field) zapcore.Core {
+	value := c.value
+	if v, ok := rateLimitValue(c.key, fields); ok {
+		value = &v
+	}
+
+	return &rateLimitCore{
+		Core:    c.Core.With(fields),
+		key:     c.key,
+		value:   value,
+		limiter: c.limiter,
+	}
+}
+
+func (c *rateLimitCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.Core.Enabled(ent.Level) {
+		return ce
+	}
+
+	// The rate limit key might be part of the fields of the log statement,
+	// which are only available on write.
+	return ce.AddCore(ent, c)
+}
+
+func (c *rateLimitCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	value, ok := rateLimitValue(c.key, fields)
+	if !ok && c.value != nil {
+		value, ok = *c.value, true
+	}
+
+	if ok && !c.limiter.allow(value, ent.Time) {
+		return nil
+	}
+
+	if ce := c.Core.Check(ent, nil); ce != nil {
+		ce.Write(fields...)
+	}
+
+	return nil
+}
+
+func rateLimitValue(key string, fields []zapcore.Field) (string, bool) {
+	for i := len(fields) - 1; i >= 0; i-- {
+		if fields[i].Key != key {
+			continue
+		}
+
+		enc := zapcore.NewMapObjectEncoder()
+		fields[i].AddTo(enc)
+
+		return fmt.Sprint(enc.Fields[key]), true
+	}
+
+	return "", false
+}
@@ -0,0 +1,75 @@
+package log
+
+import "go.uber.org/zap"
+
+// RecoverAndLog recovers from a panic and logs the recovered value
+// together with a stack trace on the error level. If the logger has
+// been configured with RePanic, it panics again with the recovered
+// value afterwards. A recovered PII field is logged under its own key
+// and resolved according to the PII mode. It has to be deferred
+// directly to work, e.g. defer logger.RecoverAndLog("worker panicked").
+func (l *Logger) RecoverAndLog(msg string) {
+	l = handleUninitialized(l)
+
+	if r := recover(); r != nil {
+		l.logRecovered(msg, r, l.rePanic)
+	}
+}
+
+// RecoverAndRePanic works like RecoverAndLog, but always panics again
+// with the recovered value after logging it, regardless of RePanic. It
+// has to be deferred directly to work, e.g.
+// defer logger.RecoverAndRePanic("worker panicked").
+func (l *Logger) RecoverAndRePanic(msg string) {
+	l = handleUninitialized(l)
+
+	if r := recover(); r != nil {
+		l.logRecovered(msg, r, true)
+	}
+}
+
+func (l *Logger) logRecovered(msg string, recovered any, rePanic bool) {
+	keyValuePairs := []any{"panic", recovered}
+	if isPIIValue(recovered) {
+		keyValuePairs = []any{recovered}
+	}
+
+	// The stack trace is added after the resolution, so that it is
+	// neither filtered nor renamed as a reserved key.
+	fields := append(l.resolveFields(keyValuePairs), zap.StackSkip(l.stackKey, 2))
+	l.logger.Errorw(msg, fields...)
+
+	if rePanic {
+		panic(recovered)
+	}
+}
+
+// isPIIValue reports whether the value is a PII field, which carries its
+// own key.
+func isPIIValue(v any) bool {
+	switch value := v.(type) {
+	case piiCipherResolver, PIIResolver:
+		return true
+	case zap.Field:
+		_, ok := piiFieldResolver(value)
+
+		return ok
+	default:
+		return false
+	}
+}
+
+// stacktraceKey returns the key of stack traces in the format of the
+// configuration or, if sinks are configured, of the first sink.
+func stacktraceKey(conf Configuration) string {
+	format := conf.Format
+	if len(conf.Sinks) > 0 {
+		format = conf.Sinks[0].Format
+	}
+
+	if key := formatEncoderConfig(format, conf).StacktraceKey; key != "" {
+		return key
+	}
+
+	return encoderConfig.StacktraceKey
+}
@@ -0,0 +1,126 @@
+package log
+
+import "testing"
+
+func TestRecoverAndLogUsesStacktraceKey(t *testing.T) {
+	l, buf := newTestLogger(t, Configuration{KeyNames: KeyNames{StacktraceKey: "trace"}})
+
+	func() {
+		defer l.RecoverAndLog("recovered")
+		panic("boom")
+	}()
+
+	lines := buf.lines(t)
+	if len(lines) != 1 {
+		t.Fatalf("expected 1 log line, got %d: %s", len(lines), buf.String())
+	}
+
+	if got := lines[0]["panic"]; got != "boom" {
+		t.Errorf("expected panic value boom, got %v", got)
+	}
+
+	if _, ok := lines[0]["trace"]; !ok {
+		t.Errorf("expected stack trace under the configured key: %s", buf.String())
+	}
+
+	if _, ok := lines[0][encoderConfig.StacktraceKey]; ok {
+		t.Errorf("expected no stack trace under the default key: %s", buf.String())
+	}
+}
+
+func TestRecoverAndLogResolvesPII(t *testing.T) {
+	l, buf := newTestLogger(t, Configuration{PIIMode: PIIModeHash})
+
+	func() {
+		defer l.RecoverAndLog("recovered")
+		panic(PII("email", "jane@example.com"))
+	}()
+
+	lines := buf.lines(t)
+	if len(lines) != 1 {
+		t.Fatalf("expected 1 log line, got %d: %s", len(lines), buf.String())
+	}
+
+	if got, want := lines[0]["email"], hash("jane@example.com"); got != want {
+		t.Errorf("expected hashed email %q, got %v", want, got)
+	}
+
+	if _, ok := lines[0]["stacktrace"]; !ok {
+		t.Errorf("expected a stack trace: %s", buf.String())
+	}
+}
+
+func TestRecoverAndLogRePanics(t *testing.T) {
+	l, buf := newTestLogger(t, Configuration{RePanic: true})
+
+	defer func() {
+		if r := recover(); r != "boom" {
+			t.Errorf("expected re-panic with boom, got %v", r)
+		}
+
+		if lines := buf.lines(t); len(lines) != 1 {
+			t.Errorf("expected 1 log line, got %d", len(lines))
+		}
+	}()
+
+	func() {
+		defer l.RecoverAndLog("recovered")
+		panic("boom")
+	}()
+}
+
+func TestRecoverAndRePanic(t *testing.T) {
+	l, buf := newTestLogger(t, Configuration{})
+
+	defer func() {
+		if r := recover(); r != "boom" {
+			t.Errorf("expected re-panic with boom, got %v", r)
+		}
+
+		if lines := buf.lines(t); len(lines) != 1 {
+			t.Errorf("expected 1 log line, got %d", len(lines))
+		}
+	}()
+
+	func() {
+		defer l.RecoverAndRePanic("recovered")
+		panic("boom")
+	}()
+}
+
+func TestPackageLevelRecoverAndRePanic(t *testing.T) {
+	buf := &syncBuffer{}
+	defer SetGlobalOutput(buf)()
+
+	defer func() {
+		if r := recover(); r != "boom" {
+			t.Errorf("expected re-panic with boom, got %v", r)
+		}
+
+		lines := buf.lines(t)
+		if len(lines) != 1 {
+			t.Fatalf("expected 1 log line, got %d", len(lines))
+		}
+
+		if got := lines[0]["panic"]; got != "boom" {
+			t.Errorf("expected panic value boom, got %v", got)
+		}
+	}()
+
+	func() {
+		defer RecoverAndRePanic("recovered")
+		panic("boom")
+	}()
+}
+
+func TestRecoverAndRePanicWithoutPanic(t *testing.T) {
+	l, buf := newTestLogger(t, Configuration{})
+
+	func() {
+		defer l.RecoverAndRePanic("recovered")
+	}()
+
+	if got := buf.String(); got != "" {
+		t.Errorf("expected no logs, got %q", got)
+	}
+}
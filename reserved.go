@@ -0,0 +1,145 @@
+package log
+
+import (
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// KeyCollisionMode specifies how fields are handled, whose keys collide
+// with the keys reserved by the logger, e.g. the message key.
+type KeyCollisionMode uint8
+
+const (
+	// KeyCollisionIgnore leaves colliding fields as is, which results
+	// in duplicate keys in the logs.
+	KeyCollisionIgnore KeyCollisionMode = 0
+
+	// KeyCollisionRename prefixes the keys of colliding fields with
+	// "fields.".
+	KeyCollisionRename KeyCollisionMode = 1
+
+	// KeyCollisionWarn leaves colliding fields as is, but logs a warning
+	// once for the logger and all loggers derived from it.
+	KeyCollisionWarn KeyCollisionMode = 2
+)
+
+const renamedKeyPrefix = "fields."
+
+var (
+	keyCollisionModes = map[KeyCollisionMode]struct{}{
+		KeyCollisionIgnore: {},
+		KeyCollisionRename: {},
+		KeyCollisionWarn:   {},
+	}
+)
+
+// The reservedKeys hold the keys reserved by a logger and how to handle
+// collisions with them.
+type reservedKeys struct {
+	mode        KeyCollisionMode
+	keys        map[string]struct{}
+	warningOnce sync.Once
+}
+
+func newReservedKeys(conf Configuration) *reservedKeys {
+	if conf.OnReservedKeyCollision == KeyCollisionIgnore {
+		return nil
+	}
+
+	keys := map[string]struct{}{
+		"app":     {},
+		"version": {},
+		"schema":  {},
+	}
+
+	if conf.IncludeSequence {
+		keys[sequenceKey] = struct{}{}
+	}
+
+	formats := []Format{conf.Format}
+	if len(conf.Sinks) > 0 {
+		formats = formats[:0]
+		for _, sink := range conf.Sinks {
+			formats = append(formats, sink.Format)
+		}
+	}
+
+	for _, format := range formats {
+		switch format {
+		case FormatGELF:
+			// all additional fields are prefixed in GELF anyway
+			continue
+		case FormatLogstash:
+			keys[logstashVersionKey] = struct{}{}
+		}
+
+		encConf := formatEncoderConfig(format, conf)
+
+		for _, key := range []string{
+			encConf.MessageKey,
+			encConf.LevelKey,
+			encConf.TimeKey,
+			encConf.NameKey,
+			encConf.CallerKey,
+			encConf.FunctionKey,
+			encConf.StacktraceKey,
+		} {
+			if key != "" {
+				keys[key] = struct{}{}
+			}
+		}
+	}
+
+	return &reservedKeys{
+		mode: conf.OnReservedKeyCollision,
+		keys: keys,
+	}
+}
+
+// handle applies the collision mode to the keys of the resolved
+// key-value pairs.
+func (r *reservedKeys) handle(l *Logger, keyValuePairs []any) []any {
+	for i := 0; i < len(keyValuePairs); i++ {
+		switch element := keyValuePairs[i].(type) {
+		case zap.Field:
+			if r.collides(l, element.Key) {
+				element.Key = renamedKeyPrefix + element.Key
+				keyValuePairs[i] = element
+			}
+		case string:
+			if i == len(keyValuePairs)-1 {
+				continue
+			}
+
+			if r.collides(l, element) {
+				keyValuePairs[i] = renamedKeyPrefix + element
+			}
+
+			i++
+		}
+	}
+
+	return keyValuePairs
+}
+
+// collides reports whether the key collides and shall be renamed. In
+// warn mode, the warning is logged on the first collision.
+func (r *reservedKeys) collides(l *Logger, key string) bool {
+	if _, ok := r.keys[key]; !ok {
+		return false
+	}
+
+	if r.mode == KeyCollisionWarn {
+		r.warningOnce.Do(func() {
+			l.logger.Desugar().WithOptions(zap.WithCaller(false)).Warn(
+				"field key collides with a reserved key of the logger",
+				zap.String("key", key),
+			)
+		})
+
+		return false
+	}
+
+	return true
+}
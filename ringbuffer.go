@@ -0,0 +1,82 @@
+package log
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// A RingBuffer retains the most recent log statements in memory, e.g. to
+// expose them via a debug endpoint while the central log pipeline is
+// down. It is added to a logger via Configuration.RingBuffer and can be
+// served via HTTP directly. The zero value retains up to
+// defaultRingBufferCapacity log statements.
+type RingBuffer struct {
+	mu      sync.Mutex
+	entries []string
+	next    int
+	full    bool
+}
+
+// defaultRingBufferCapacity is the capacity of a zero value RingBuffer.
+const defaultRingBufferCapacity = 100
+
+// NewRingBuffer creates a ring buffer retaining up to capacity log
+// statements. If capacity is smaller than 1, a capacity of 1 is used.
+func NewRingBuffer(capacity int) *RingBuffer {
+	if capacity < 1 {
+		capacity = 1
+	}
+
+	return &RingBuffer{entries: make([]string, capacity)}
+}
+
+// Write stores a single encoded log statement and evicts the oldest one,
+// if the buffer is full.
+func (b *RingBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.entries) == 0 {
+		b.entries = make([]string, defaultRingBufferCapacity)
+	}
+
+	b.entries[b.next] = strings.TrimRight(string(p), "\r\n")
+	b.next = (b.next + 1) % len(b.entries)
+
+	if b.next == 0 {
+		b.full = true
+	}
+
+	return len(p), nil
+}
+
+// Recent returns the retained log statements from the oldest to the
+// newest.
+func (b *RingBuffer) Recent() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.full {
+		out := make([]string, b.next)
+		copy(out, b.entries[:b.next])
+
+		return out
+	}
+
+	out := make([]string, 0, len(b.entries))
+	out = append(out, b.entries[b.next:]...)
+	out = append(out, b.entries[:b.next]...)
+
+	return out
+}
+
+// ServeHTTP writes the retained log statements one per line from the
+// oldest to the newest.
+func (b *RingBuffer) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+
+	for _, entry := range b.Recent() {
+		_, _ = w.Write([]byte(entry + "\n"))
+	}
+}
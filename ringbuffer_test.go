@@ -0,0 +1,51 @@
+package log
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+)
+
+func TestRingBufferEvictsOldest(t *testing.T) {
+	b := NewRingBuffer(2)
+
+	for i := 0; i < 3; i++ {
+		_, _ = b.Write([]byte(strconv.Itoa(i) + "\n"))
+	}
+
+	got := b.Recent()
+	if len(got) != 2 || got[0] != "1" || got[1] != "2" {
+		t.Errorf("expected [1 2], got %v", got)
+	}
+}
+
+func TestZeroValueRingBuffer(t *testing.T) {
+	b := &RingBuffer{}
+	l, _ := newTestLogger(t, Configuration{RingBuffer: b})
+
+	for i := 0; i < defaultRingBufferCapacity+1; i++ {
+		l.Infow("buffered", "i", i)
+	}
+
+	got := b.Recent()
+	if len(got) != defaultRingBufferCapacity {
+		t.Fatalf("expected %d retained statements, got %d", defaultRingBufferCapacity, len(got))
+	}
+
+	for _, tc := range []struct {
+		entry string
+		want  float64
+	}{
+		{entry: got[0], want: 1},
+		{entry: got[len(got)-1], want: defaultRingBufferCapacity},
+	} {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(tc.entry), &entry); err != nil {
+			t.Fatalf("invalid log line %q: %v", tc.entry, err)
+		}
+
+		if entry["i"] != tc.want {
+			t.Errorf("expected i to be %v, got %v", tc.want, entry["i"])
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package log
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// SamplingConfiguration configures the sampling of log statements. Per
+// Tick, the first Initial log statements with the same level and message
+// are logged, afterwards only every Thereafter-th of them.
+type SamplingConfiguration struct {
+	// Tick sets the interval in which the sampling counters are reset.
+	// If not set, one second is used.
+	Tick time.Duration
+
+	// Initial sets the number of log statements with the same level
+	// and message, which are logged per tick before sampling starts.
+	Initial int
+
+	// Thereafter sets that only every Thereafter-th log statement with
+	// the same level and message is logged after Initial has been
+	// exceeded. If set to 0, all of them are dropped.
+	Thereafter int
+
+	// Keep, if set, is called for every log statement before sampling.
+	// Log statements for which it returns true are always logged and
+	// do not count towards the sampling, e.g. to always keep errors.
+	Keep func(entry zapcore.Entry) bool
+
+	// Hook, if set, is called with every sampling decision for log
+	// statements that are subject to sampling. It shall be thread-safe.
+	Hook func(entry zapcore.Entry, decision zapcore.SamplingDecision)
+}
+
+func validateSamplingConf(conf SamplingConfiguration) error {
+	if conf.Tick < 0 {
+		return errors.New("invalid negative sampling tick")
+	}
+
+	if conf.Initial < 0 {
+		return errors.New("invalid negative initial sampling count")
+	}
+
+	if conf.Thereafter < 0 {
+		return errors.New("invalid negative thereafter sampling count")
+	}
+
+	return nil
+}
+
+func newSamplingCore(core zapcore.Core, conf SamplingConfiguration) zapcore.Core {
+	tick := conf.Tick
+	if tick == 0 {
+		tick = time.Second
+	}
+
+	var opts []zapcore.SamplerOption
+	if conf.Hook != nil {
+		opts = append(opts, zapcore.SamplerHook(conf.Hook))
+	}
+
+	sampled := zapcore.NewSamplerWithOptions(core, tick, conf.Initial, conf.Thereafter, opts...)
+
+	if conf.Keep == nil {
+		return sampled
+	}
+
+	return &keepCore{
+		Core:      sampled,
+		unsampled: core,
+		keep:      conf.Keep,
+	}
+}
+
+// The keepCore bypasses sampling for all log statements for which the
+// keep function returns true.
+type keepCore struct {
+	zapcore.Core
+	unsampled zapcore.Core
+	keep      func(entry zapcore.Entry) bool
+}
+
+func (c *keepCore) With(fields []zapcore.Field) zapcore.Core {
+	return &keepCore{
+		Core:      c.Core.With(fields),
+		unsampled: c.unsampled.With(fields),
+		keep:      c.keep,
+	}
+}
+
+func (c *keepCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.keep(ent) {
+		return c.unsampled.Check(ent, ce)
+	}
+
+	return c.Core.Check(ent, ce)
+}
+
+// A SamplingHookFunc decides for every log statement, whether it is
+// logged or dropped, e.g. based on a tenant field. The fields include
+// the ones added via With and have already been resolved according to
+// the PII mode. It shall be thread-safe.
+type SamplingHookFunc func(level Level, msg string, fields []zap.Field) zapcore.SamplingDecision
+
+// The samplingHookCore drops all log statements for which the hook
+// decides so.
+type samplingHookCore struct {
+	zapcore.Core
+	fields []zapcore.Field
+	hook   SamplingHookFunc
+	stats  *stats
+}
+
+func newSamplingHookCore(core zapcore.Core, hook SamplingHookFunc, s *stats) zapcore.Core {
+	return &samplingHookCore{
+		Core:  core,
+		hook:  hook,
+		stats: s,
+	}
+}
+
+func (c *samplingHookCore) With(fields []zapcore.Field) zapcore.Core {
+	all := make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	all = append(all, c.fields...)
+	all = append(all, fields...)
+
+	return &samplingHookCore{
+		Core:   c.Core.With(fields),
+		fields: all,
+		hook:   c.hook,
+		stats:  c.stats,
+	}
+}
+
+func (c *samplingHookCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.Core.Enabled(ent.Level) {
+		return ce
+	}
+
+	// The hook decides based on the fields of the log statement, which
+	// are only available on write.
+	return ce.AddCore(ent, c)
+}
+
+func (c *samplingHookCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	all := make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	all = append(all, c.fields...)
+	all = append(all, fields...)
+
+	if c.hook(Level(ent.Level), ent.Message, all)&zapcore.LogDropped != 0 {
+		atomic.AddUint64(&c.stats.sampled, 1)
+
+		return nil
+	}
+
+	if ce := c.Core.Check(ent, nil); ce != nil {
+		ce.Write(fields...)
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package log
+
+import (
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const sequenceKey = "seq"
+
+// The sequenceCore adds an incrementing sequence number to every log
+// statement. The counter is shared with all cores derived from it via
+// With, so the sequence numbers reflect the order in which the log
+// statements of a logger and its children have been written. The number
+// is only assigned on write, so that log statements dropped by sampling
+// or rate limiting do not leave gaps.
+type sequenceCore struct {
+	zapcore.Core
+	counter *uint64
+}
+
+func newSequenceCore(core zapcore.Core) zapcore.Core {
+	return &sequenceCore{
+		Core:    core,
+		counter: new(uint64),
+	}
+}
+
+func (c *sequenceCore) With(fields []zapcore.Field) zapcore.Core {
+	return &sequenceCore{
+		Core:    c.Core.With(fields),
+		counter: c.counter,
+	}
+}
+
+func (c *sequenceCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.Core.Enabled(ent.Level) {
+		return ce
+	}
+
+	return ce.AddCore(ent, c)
+}
+
+func (c *sequenceCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	ce := c.Core.Check(ent, nil)
+	if ce == nil {
+		return nil
+	}
+
+	all := make([]zapcore.Field, 0, len(fields)+1)
+	all = append(all, fields...)
+	all = append(all, zap.Uint64(sequenceKey, atomic.AddUint64(c.counter, 1)))
+
+	ce.Write(all...)
+
+	return nil
+}
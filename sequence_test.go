@@ -0,0 +1,56 @@
+package log
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestSequenceHasNoGapsForDroppedStatements(t *testing.T) {
+	l, buf := newTestLogger(t, Configuration{
+		IncludeSequence: true,
+		SamplingHook: func(_ Level, msg string, _ []zap.Field) zapcore.SamplingDecision {
+			if msg == "drop" {
+				return zapcore.LogDropped
+			}
+
+			return zapcore.LogSampled
+		},
+	})
+
+	l.Info("keep")
+	l.Info("drop")
+	l.With("k", "v").(*Logger).Info("keep")
+	l.Info("drop")
+	l.Info("keep")
+
+	lines := buf.lines(t)
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 log lines, got %d: %s", len(lines), buf.String())
+	}
+
+	for i, line := range lines {
+		if got, want := line[sequenceKey], float64(i+1); got != want {
+			t.Errorf("expected sequence number %v in line %d, got %v", want, i, got)
+		}
+	}
+}
+
+func TestSequenceKeyIsReserved(t *testing.T) {
+	l, buf := newTestLogger(t, Configuration{
+		IncludeSequence:        true,
+		OnReservedKeyCollision: KeyCollisionRename,
+	})
+
+	l.Infow("shadowed", sequenceKey, "user value")
+
+	lines := buf.lines(t)
+	if got := lines[0][sequenceKey]; got != float64(1) {
+		t.Errorf("expected sequence number 1, got %v", got)
+	}
+
+	if got := lines[0]["fields."+sequenceKey]; got != "user value" {
+		t.Errorf("expected renamed user field, got %v", got)
+	}
+}
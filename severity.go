@@ -0,0 +1,32 @@
+package log
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+const severityNumberKey = "severity_number"
+
+// The severityNumberEncoder adds the numeric syslog severity to every
+// encoded log statement.
+type severityNumberEncoder struct {
+	zapcore.Encoder
+}
+
+func (e *severityNumberEncoder) Clone() zapcore.Encoder {
+	return &severityNumberEncoder{Encoder: e.Encoder.Clone()}
+}
+
+func (e *severityNumberEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	out := make([]zapcore.Field, 0, len(fields)+1)
+	out = append(out, fields...)
+	out = append(out, zap.Int64(severityNumberKey, syslogSeverity(ent.Level)))
+
+	return e.Encoder.EncodeEntry(ent, out)
+}
+
+// numericLevelEncoder encodes the level as its numeric syslog severity.
+func numericLevelEncoder(lvl zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+	enc.AppendInt64(syslogSeverity(lvl))
+}
@@ -0,0 +1,49 @@
+package log
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// InstallShutdownFlush installs a handler, which syncs the logger once
+// one of the given signals is received. If no signals are given, SIGINT
+// and SIGTERM are used. After syncing, the handler uninstalls itself and
+// raises the received signal again, so that the default behavior of the
+// application, e.g. terminating, is preserved. Applications with their
+// own handler for the signal will therefore receive it twice. The
+// returned function uninstalls the handler.
+func (l *Logger) InstallShutdownFlush(signals ...os.Signal) func() {
+	l = handleUninitialized(l)
+
+	if len(signals) == 0 {
+		signals = []os.Signal{syscall.SIGINT, syscall.SIGTERM}
+	}
+
+	sigChan := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(sigChan, signals...)
+
+	go func() {
+		select {
+		case sig := <-sigChan:
+			signal.Stop(sigChan)
+			_ = l.Sync()
+
+			if p, err := os.FindProcess(os.Getpid()); err == nil {
+				_ = p.Signal(sig)
+			}
+		case <-done:
+		}
+	}()
+
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			signal.Stop(sigChan)
+			close(done)
+		})
+	}
+}
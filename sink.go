@@ -0,0 +1,173 @@
+package log
+
+import (
+	"io"
+	"os"
+	"reflect"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// A Sink represents a single destination for logs.
+type Sink struct {
+	// Writer receives the logs of the sink. It must not be nil.
+	Writer io.Writer
+
+	// Format indicates how log statements written to the sink are
+	// encoded.
+	Format Format
+
+	// MinimumLogLevel sets the minimum level of logs that will be
+	// written to the sink. It is combined with the MinimumLogLevel of
+	// the configuration, i.e. the higher of both applies.
+	MinimumLogLevel Level
+
+	// MaximumLogLevel, if set, sets the maximum level of logs that
+	// will be written to the sink. This allows splitting logs between
+	// sinks based on their level.
+	MaximumLogLevel *Level
+
+	// Compress gzips all logs written to the sink, e.g. for archival
+	// files. The compressed data is flushed on Sync and the gzip stream
+	// is terminated on Close of the logger. Compressed logs can't be
+	// tailed by humans.
+	Compress bool
+}
+
+func (s Sink) levelEnabler() zapcore.LevelEnabler {
+	minLvl := zapcore.Level(s.MinimumLogLevel)
+
+	if s.MaximumLogLevel == nil {
+		return zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
+			return lvl >= minLvl
+		})
+	}
+
+	maxLvl := zapcore.Level(*s.MaximumLogLevel)
+
+	return zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
+		return lvl >= minLvl && lvl <= maxLvl
+	})
+}
+
+func validateSink(s Sink) error {
+	if isNilWriter(s.Writer) {
+		return errors.New("sink writer must not be nil")
+	}
+
+	if _, ok := formats[s.Format]; !ok {
+		return errors.New("invalid format")
+	}
+
+	if _, ok := logLevels[s.MinimumLogLevel]; !ok {
+		return errors.New("invalid minimum log level")
+	}
+
+	if s.MaximumLogLevel != nil {
+		if _, ok := logLevels[*s.MaximumLogLevel]; !ok {
+			return errors.New("invalid maximum log level")
+		}
+	}
+
+	return nil
+}
+
+// defaultSinks expresses the output settings of a configuration as
+// sinks. In OutputStdOutAndStdErr mode, everything below the stdErr
+// threshold level goes to stdout and all else to stderr. The sinks do
+// not restrict the minimum log level, as it is enforced per logger.
+func defaultSinks(conf Configuration, stdErrThresholdLevel Level) []Sink {
+	if conf.Output != nil {
+		return []Sink{{Writer: conf.Output, Format: conf.Format, MinimumLogLevel: DebugLevel}}
+	}
+
+	switch conf.OutputMode {
+	case OutputStdOut:
+		return []Sink{{Writer: os.Stdout, Format: conf.Format, MinimumLogLevel: DebugLevel}}
+	case OutputStdErr:
+		return []Sink{{Writer: os.Stderr, Format: conf.Format, MinimumLogLevel: DebugLevel}}
+	}
+
+	if stdErrThresholdLevel <= DebugLevel {
+		return []Sink{{Writer: os.Stderr, Format: conf.Format, MinimumLogLevel: DebugLevel}}
+	}
+
+	lowPrioMax := stdErrThresholdLevel - 1
+
+	return []Sink{
+		{Writer: os.Stdout, Format: conf.Format, MinimumLogLevel: DebugLevel, MaximumLogLevel: &lowPrioMax},
+		{Writer: os.Stderr, Format: conf.Format, MinimumLogLevel: stdErrThresholdLevel},
+	}
+}
+
+// isNilWriter reports whether the writer is nil, including interfaces
+// holding a nil pointer, which would only fail once logs are written.
+func isNilWriter(w io.Writer) bool {
+	if w == nil {
+		return true
+	}
+
+	v := reflect.ValueOf(w)
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Chan, reflect.Func, reflect.Interface, reflect.Slice:
+		return v.IsNil()
+	default:
+		return false
+	}
+}
+
+// Tee returns a new logger, which additionally writes all log statements
+// to the given core, e.g. to capture logs in a buffer during an
+// investigation. The core receives the fields of the logger,
+// i.e. the ones from the configuration and the ones added via With,
+// except for the ones added via WithLazy. It decides on its own, which
+// levels it accepts. The original logger remains unchanged.
+func (l *Logger) Tee(extra zapcore.Core) ILogger {
+	l = handleUninitialized(l)
+
+	if extra == nil {
+		return l
+	}
+
+	// The root logger only carries the static fields, the accumulated
+	// ones are added, when the logger is rebuilt.
+	rootExtra := extra.With(l.static)
+
+	fields := make([]zap.Field, 0, len(l.static)+len(l.fields))
+	fields = append(fields, l.static...)
+	fields = append(fields, l.fields...)
+	loggerExtra := extra.With(fields)
+
+	out := l.withSugaredLogger(l.logger.Desugar().WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return zapcore.NewTee(core, loggerExtra)
+	})).Sugar())
+	out.root = l.root.Desugar().WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return zapcore.NewTee(core, rootExtra)
+	})).Sugar()
+
+	return out
+}
+
+// appendCloser appends the writer to the closers, if it is closeable and
+// not one of the standard streams. Writers shared by several sinks are
+// only appended once.
+func appendCloser(closers []io.Closer, w io.Writer) []io.Closer {
+	closer, ok := w.(io.Closer)
+	if !ok || w == io.Writer(os.Stdout) || w == io.Writer(os.Stderr) {
+		return closers
+	}
+
+	if !reflect.TypeOf(closer).Comparable() {
+		return append(closers, closer)
+	}
+
+	for _, c := range closers {
+		if c == closer {
+			return closers
+		}
+	}
+
+	return append(closers, closer)
+}
@@ -0,0 +1,64 @@
+package log
+
+import (
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestTeeAddsFieldsOfTheLogger(t *testing.T) {
+	l, _ := newTestLogger(t, Configuration{ApplicationName: "app", Version: "1.0.0"})
+
+	extra := &syncBuffer{}
+	extraCore := zapcore.NewCore(zapcore.NewJSONEncoder(encoderConfig), zapcore.AddSync(extra), zapcore.DebugLevel)
+
+	teed := l.WithFields(map[string]any{"req": "r1"}).(*Logger).Tee(extraCore)
+	teed.Infow("mirrored", "k", 1)
+	teed.Without("req").Infow("without request")
+
+	lines := extra.lines(t)
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 log lines, got %d: %s", len(lines), extra.String())
+	}
+
+	for key, want := range map[string]any{"app": "app", "version": "1.0.0", "req": "r1", "k": float64(1)} {
+		if got := lines[0][key]; got != want {
+			t.Errorf("expected %q to be %v, got %v", key, want, got)
+		}
+	}
+
+	if got := lines[1]["app"]; got != "app" {
+		t.Errorf("expected app field after rebuilding, got %v", got)
+	}
+
+	if _, ok := lines[1]["req"]; ok {
+		t.Errorf("expected req field to be removed, got %v", lines[1])
+	}
+}
+
+func TestSinkLevelIsCombinedWithGlobalMinimumLevel(t *testing.T) {
+	debugSink, errorSink := &syncBuffer{}, &syncBuffer{}
+
+	l, err := NewLogger(Configuration{
+		MinimumLogLevel: WarnLevel,
+		Sinks: []Sink{
+			{Writer: debugSink, MinimumLogLevel: DebugLevel},
+			{Writer: errorSink, MinimumLogLevel: ErrorLevel},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	l.Info("info")
+	l.Warn("warn")
+	l.Error("error")
+
+	if got := len(debugSink.lines(t)); got != 2 {
+		t.Errorf("expected 2 log lines in the debug sink, got %d: %s", got, debugSink.String())
+	}
+
+	if got := len(errorSink.lines(t)); got != 1 {
+		t.Errorf("expected 1 log line in the error sink, got %d: %s", got, errorSink.String())
+	}
+}
@@ -0,0 +1,66 @@
+package log
+
+import (
+	"sort"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// The sortedFieldsCore sorts the fields of every log statement including
+// the ones added via With alphabetically by key. Namespaces keep their
+// position and the fields nested under them are sorted separately.
+type sortedFieldsCore struct {
+	zapcore.Core
+	fields []zapcore.Field
+}
+
+func newSortedFieldsCore(core zapcore.Core) zapcore.Core {
+	return &sortedFieldsCore{Core: core}
+}
+
+func (c *sortedFieldsCore) With(fields []zapcore.Field) zapcore.Core {
+	all := make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	all = append(all, c.fields...)
+	all = append(all, fields...)
+
+	// The fields are only passed to the wrapped core on write, so that
+	// they can be sorted together with the ones of the log statement.
+	return &sortedFieldsCore{
+		Core:   c.Core,
+		fields: all,
+	}
+}
+
+func (c *sortedFieldsCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.Core.Enabled(ent.Level) {
+		return ce
+	}
+
+	return ce.AddCore(ent, c)
+}
+
+func (c *sortedFieldsCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	all := make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	all = append(all, c.fields...)
+	all = append(all, fields...)
+
+	start := 0
+	for i := 0; i <= len(all); i++ {
+		if i < len(all) && all[i].Type != zapcore.NamespaceType {
+			continue
+		}
+
+		segment := all[start:i]
+		sort.SliceStable(segment, func(a, b int) bool {
+			return segment[a].Key < segment[b].Key
+		})
+
+		start = i + 1
+	}
+
+	if ce := c.Core.Check(ent, nil); ce != nil {
+		ce.Write(all...)
+	}
+
+	return nil
+}
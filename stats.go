@@ -0,0 +1,100 @@
+package log
+
+import (
+	"sync/atomic"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Stats holds statistics about the log statements of a logger and all
+// loggers derived from it.
+type Stats struct {
+	// RateLimited is the number of log statements dropped due to the
+	// rate limit.
+	RateLimited uint64
+
+	// Sampled is the number of log statements dropped by the
+	// SamplingHook.
+	Sampled uint64
+}
+
+type stats struct {
+	rateLimited uint64
+	sampled     uint64
+	piiResolved [PIIModeEncrypt + 1]uint64
+	logged      [FatalLevel - DebugLevel + 1]uint64
+}
+
+// countLogged counts a log statement written on the level of the entry.
+// It is registered as a hook on the innermost core, so that log
+// statements dropped by sampling or rate limiting are not counted.
+func (s *stats) countLogged(ent zapcore.Entry) error {
+	i := int(ent.Level) - int(DebugLevel)
+	if i < 0 || i >= len(s.logged) {
+		return nil
+	}
+
+	atomic.AddUint64(&s.logged[i], 1)
+
+	return nil
+}
+
+// countPIIResolved counts a PII field resolved in the given PII mode.
+func (s *stats) countPIIResolved(piiMode PIIMode) {
+	if s == nil || int(piiMode) >= len(s.piiResolved) {
+		return
+	}
+
+	atomic.AddUint64(&s.piiResolved[piiMode], 1)
+}
+
+// Stats returns the statistics of the logger. These are shared with all
+// loggers derived from it.
+func (l *Logger) Stats() Stats {
+	l = handleUninitialized(l)
+
+	if l.stats == nil {
+		return Stats{}
+	}
+
+	return Stats{
+		RateLimited: atomic.LoadUint64(&l.stats.rateLimited),
+		Sampled:     atomic.LoadUint64(&l.stats.sampled),
+	}
+}
+
+// PIIStats returns the number of PII fields resolved per PII mode by the
+// logger and all loggers derived from it, e.g. to verify that PII is
+// actually hashed or removed.
+func (l *Logger) PIIStats() map[PIIMode]uint64 {
+	l = handleUninitialized(l)
+
+	out := make(map[PIIMode]uint64, len(piiModes))
+	for mode := range piiModes {
+		out[mode] = 0
+
+		if l.stats != nil {
+			out[mode] = atomic.LoadUint64(&l.stats.piiResolved[mode])
+		}
+	}
+
+	return out
+}
+
+// LevelStats returns the number of log statements written per level by
+// the logger and all loggers derived from it, e.g. to export the log rate
+// as a metric.
+func (l *Logger) LevelStats() map[Level]uint64 {
+	l = handleUninitialized(l)
+
+	out := make(map[Level]uint64, len(logLevels))
+	for level := range logLevels {
+		out[level] = 0
+
+		if l.stats != nil {
+			out[level] = atomic.LoadUint64(&l.stats.logged[level-DebugLevel])
+		}
+	}
+
+	return out
+}
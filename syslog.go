@@ -0,0 +1,51 @@
+package log
+
+// SyslogFacility specifies the syslog facility logs are sent with.
+type SyslogFacility uint8
+
+const (
+	SyslogFacilityUser   SyslogFacility = 0
+	SyslogFacilityDaemon SyslogFacility = 1
+	SyslogFacilityLocal0 SyslogFacility = 2
+	SyslogFacilityLocal1 SyslogFacility = 3
+	SyslogFacilityLocal2 SyslogFacility = 4
+	SyslogFacilityLocal3 SyslogFacility = 5
+	SyslogFacilityLocal4 SyslogFacility = 6
+	SyslogFacilityLocal5 SyslogFacility = 7
+	SyslogFacilityLocal6 SyslogFacility = 8
+	SyslogFacilityLocal7 SyslogFacility = 9
+)
+
+var (
+	syslogFacilities = map[SyslogFacility]struct{}{
+		SyslogFacilityUser:   {},
+		SyslogFacilityDaemon: {},
+		SyslogFacilityLocal0: {},
+		SyslogFacilityLocal1: {},
+		SyslogFacilityLocal2: {},
+		SyslogFacilityLocal3: {},
+		SyslogFacilityLocal4: {},
+		SyslogFacilityLocal5: {},
+		SyslogFacilityLocal6: {},
+		SyslogFacilityLocal7: {},
+	}
+)
+
+// SyslogConfiguration configures the connection to a syslog daemon. The
+// levels of log statements are mapped to the respective syslog
+// severities.
+type SyslogConfiguration struct {
+	// Network and Address specify the syslog daemon to connect to, e.g.
+	// "udp" and "syslog.example.com:514". If Network is empty, the
+	// local syslog daemon is used.
+	Network string
+	Address string
+
+	// Facility sets the syslog facility. If not set, the user facility
+	// is used.
+	Facility SyslogFacility
+
+	// Tag sets the syslog tag. If not set, the name of the executable
+	// is used.
+	Tag string
+}
@@ -0,0 +1,12 @@
+//go:build windows || plan9
+
+package log
+
+import (
+	"github.com/pkg/errors"
+	"go.uber.org/zap/zapcore"
+)
+
+func newSyslogCore(_ SyslogConfiguration, _ zapcore.Encoder) (zapcore.Core, func() error, error) {
+	return nil, nil, errors.New("syslog is not supported on this platform")
+}
@@ -0,0 +1,93 @@
+//go:build !windows && !plan9
+
+package log
+
+import (
+	"log/syslog"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap/zapcore"
+)
+
+var syslogPriorities = map[SyslogFacility]syslog.Priority{
+	SyslogFacilityUser:   syslog.LOG_USER,
+	SyslogFacilityDaemon: syslog.LOG_DAEMON,
+	SyslogFacilityLocal0: syslog.LOG_LOCAL0,
+	SyslogFacilityLocal1: syslog.LOG_LOCAL1,
+	SyslogFacilityLocal2: syslog.LOG_LOCAL2,
+	SyslogFacilityLocal3: syslog.LOG_LOCAL3,
+	SyslogFacilityLocal4: syslog.LOG_LOCAL4,
+	SyslogFacilityLocal5: syslog.LOG_LOCAL5,
+	SyslogFacilityLocal6: syslog.LOG_LOCAL6,
+	SyslogFacilityLocal7: syslog.LOG_LOCAL7,
+}
+
+func newSyslogCore(conf SyslogConfiguration, encoder zapcore.Encoder) (zapcore.Core, func() error, error) {
+	w, err := syslog.Dial(conf.Network, conf.Address, syslogPriorities[conf.Facility]|syslog.LOG_INFO, conf.Tag)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not connect to the syslog daemon")
+	}
+
+	return &syslogCore{
+		LevelEnabler: zapcore.DebugLevel,
+		encoder:      encoder,
+		writer:       w,
+	}, w.Close, nil
+}
+
+// The syslogCore writes log statements to a syslog daemon with the
+// syslog severity matching their level.
+type syslogCore struct {
+	zapcore.LevelEnabler
+	encoder zapcore.Encoder
+	writer  *syslog.Writer
+}
+
+func (c *syslogCore) With(fields []zapcore.Field) zapcore.Core {
+	encoder := c.encoder.Clone()
+	for _, f := range fields {
+		f.AddTo(encoder)
+	}
+
+	return &syslogCore{
+		LevelEnabler: c.LevelEnabler,
+		encoder:      encoder,
+		writer:       c.writer,
+	}
+}
+
+func (c *syslogCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+
+	return ce
+}
+
+func (c *syslogCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	buf, err := c.encoder.EncodeEntry(ent, fields)
+	if err != nil {
+		return errors.Wrap(err, "could not encode log statement for syslog")
+	}
+	defer buf.Free()
+
+	msg := strings.TrimSuffix(buf.String(), "\n")
+
+	switch syslogSeverity(ent.Level) {
+	case 7:
+		return c.writer.Debug(msg)
+	case 6:
+		return c.writer.Info(msg)
+	case 4:
+		return c.writer.Warning(msg)
+	case 3:
+		return c.writer.Err(msg)
+	default:
+		return c.writer.Crit(msg)
+	}
+}
+
+func (c *syslogCore) Sync() error {
+	return nil
+}
@@ -0,0 +1,78 @@
+package log
+
+import (
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	truncatedSuffix    = "…"
+	truncatedKeySuffix = "_truncated"
+)
+
+// truncateFields truncates all string values in the resolved key-value
+// pairs, which are longer than the given number of bytes. Each truncated
+// value is followed by a marker field, whose key is the key of the value
+// with the suffix "_truncated".
+func truncateFields(maxBytes int, keyValuePairs []any) []any {
+	out := make([]any, 0, len(keyValuePairs))
+
+	for i := 0; i < len(keyValuePairs); i++ {
+		switch element := keyValuePairs[i].(type) {
+		case zap.Field:
+			if element.Type != zapcore.StringType || len(element.String) <= maxBytes {
+				out = append(out, element)
+
+				continue
+			}
+
+			out = append(out,
+				zap.String(element.Key, truncateString(element.String, maxBytes)),
+				zap.Bool(element.Key+truncatedKeySuffix, true),
+			)
+		case string:
+			if i == len(keyValuePairs)-1 {
+				out = append(out, element)
+
+				continue
+			}
+
+			i++
+
+			value, ok := keyValuePairs[i].(string)
+			if !ok || len(value) <= maxBytes {
+				out = append(out, element, keyValuePairs[i])
+
+				continue
+			}
+
+			out = append(out,
+				zap.String(element, truncateString(value, maxBytes)),
+				zap.Bool(element+truncatedKeySuffix, true),
+			)
+		default:
+			out = append(out, element)
+		}
+	}
+
+	return out
+}
+
+// truncateString cuts the string to at most maxBytes bytes including
+// the appended ellipsis without splitting a multibyte character. If the
+// limit is too small to fit the ellipsis, it is omitted.
+func truncateString(s string, maxBytes int) string {
+	suffix := truncatedSuffix
+	if maxBytes <= len(suffix) {
+		suffix = ""
+	}
+
+	end := maxBytes - len(suffix)
+	for end > 0 && !utf8.RuneStart(s[end]) {
+		end--
+	}
+
+	return s[:end] + suffix
+}
@@ -0,0 +1,75 @@
+package log
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncateString(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       string
+		maxBytes int
+		want     string
+	}{
+		{name: "ascii", in: "abcdefghij", maxBytes: 8, want: "abcde…"},
+		{name: "two-byte runes", in: "äöüäöü", maxBytes: 8, want: "äö…"},
+		{name: "boundary inside rune", in: "äöüäöü", maxBytes: 7, want: "äö…"},
+		{name: "four-byte runes", in: "😀😀😀", maxBytes: 10, want: "😀…"},
+		{name: "cjk", in: "日本語のテキスト", maxBytes: 12, want: "日本語…"},
+		{name: "limit too small for ellipsis", in: "äöü", maxBytes: 3, want: "ä"},
+		{name: "limit smaller than first rune", in: "😀😀", maxBytes: 2, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := truncateString(tt.in, tt.maxBytes)
+
+			if got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+
+			if len(got) > tt.maxBytes {
+				t.Errorf("expected at most %d bytes, got %d", tt.maxBytes, len(got))
+			}
+
+			if !utf8.ValidString(got) {
+				t.Errorf("expected valid UTF-8, got %q", got)
+			}
+		})
+	}
+}
+
+func TestMaxFieldValueBytes(t *testing.T) {
+	l, buf := newTestLogger(t, Configuration{MaxFieldValueBytes: 10})
+
+	l.With("body", strings.Repeat("ü", 10)).(*Logger).Infow("request", "short", "ok", "blob", strings.Repeat("x", 20))
+
+	lines := buf.lines(t)
+	if len(lines) != 1 {
+		t.Fatalf("expected 1 log line, got %d: %s", len(lines), buf.String())
+	}
+
+	for key, want := range map[string]any{
+		"body":           "üüü…",
+		"body_truncated": true,
+		"blob":           "xxxxxxx…",
+		"blob_truncated": true,
+		"short":          "ok",
+	} {
+		if got := lines[0][key]; got != want {
+			t.Errorf("expected %q to be %v, got %v", key, want, got)
+		}
+	}
+
+	if _, ok := lines[0]["short_truncated"]; ok {
+		t.Error("expected no marker for a value within the limit")
+	}
+}
+
+func TestMaxFieldValueBytesNegative(t *testing.T) {
+	if _, err := NewLogger(Configuration{MaxFieldValueBytes: -1}); err == nil {
+		t.Error("expected an error for a negative limit")
+	}
+}
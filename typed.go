@@ -0,0 +1,103 @@
+package log
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// A Field is a typed key-value pair, e.g. created via zap.String or
+// TimeField, which is logged without the reflection and allocations of
+// the w-methods.
+type Field = zap.Field
+
+// DebugFields logs the message and typed fields on the debug level.
+func (l *Logger) DebugFields(msg string, fields ...Field) {
+	l = handleUninitialized(l)
+	l.writeFields(zapcore.DebugLevel, msg, fields)
+}
+
+// InfoFields logs the message and typed fields on the info level.
+func (l *Logger) InfoFields(msg string, fields ...Field) {
+	l = handleUninitialized(l)
+	l.writeFields(zapcore.InfoLevel, msg, fields)
+}
+
+// WarnFields logs the message and typed fields on the warn level.
+func (l *Logger) WarnFields(msg string, fields ...Field) {
+	l = handleUninitialized(l)
+	l.writeFields(zapcore.WarnLevel, msg, fields)
+}
+
+// ErrorFields logs the message and typed fields on the error level.
+func (l *Logger) ErrorFields(msg string, fields ...Field) {
+	l = handleUninitialized(l)
+	l.writeFields(zapcore.ErrorLevel, msg, fields)
+}
+
+// DPanicFields logs the message and typed fields on the dpanic level.
+// In development mode, it panics afterwards.
+func (l *Logger) DPanicFields(msg string, fields ...Field) {
+	l = handleUninitialized(l)
+	l.writeFields(zapcore.DPanicLevel, msg, fields)
+}
+
+// PanicFields logs the message and typed fields on the panic level and
+// panics afterwards.
+func (l *Logger) PanicFields(msg string, fields ...Field) {
+	l = handleUninitialized(l)
+	l.writeFields(zapcore.PanicLevel, msg, fields)
+}
+
+// FatalFields logs the message and typed fields on the fatal level and
+// runs os.exit(1) at the end.
+func (l *Logger) FatalFields(msg string, fields ...Field) {
+	l = handleUninitialized(l)
+	l.writeFields(zapcore.FatalLevel, msg, fields)
+}
+
+// writeFields logs the typed fields, if the level is enabled.
+func (l *Logger) writeFields(level zapcore.Level, msg string, fields []Field) {
+	ce := l.base.Check(level, msg)
+	if ce == nil {
+		return
+	}
+
+	l.writeCheckedFields(ce, fields)
+}
+
+// writeCheckedFields writes the checked entry with the typed fields. PII
+// fields, filtered keys, long values and reserved key collisions are only
+// handled, if present or configured, to keep the hot path free of
+// allocations.
+func (l *Logger) writeCheckedFields(ce *zapcore.CheckedEntry, fields []Field) {
+	if l.keyFilter == nil && l.reservedKeys == nil && l.maxFieldValueBytes == 0 && !hasPIIFields(fields) {
+		ce.Write(fields...)
+
+		return
+	}
+
+	keyValuePairs := make([]any, len(fields))
+	for i, f := range fields {
+		keyValuePairs[i] = f
+	}
+
+	ce.Write(toFields(l.resolveFields(keyValuePairs))...)
+}
+
+// hasPIIFields reports whether any of the fields has been created via
+// PIIField.
+func hasPIIFields(fields []Field) bool {
+	for _, f := range fields {
+		if _, ok := piiFieldResolver(f); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// newFieldsBase creates the logger used for typed fields, which skips
+// the additional frame of writeFields when determining the caller.
+func newFieldsBase(sugared *zap.SugaredLogger) *zap.Logger {
+	return sugared.Desugar().WithOptions(zap.AddCallerSkip(1))
+}
@@ -0,0 +1,126 @@
+package log
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestTypedFieldsResolvePII(t *testing.T) {
+	l, buf := newTestLogger(t, Configuration{PIIMode: PIIModeHash})
+
+	l.InfoFields("typed", zap.String("user", "jane"), PIIField("email", "jane@example.com"))
+
+	lines := buf.lines(t)
+	if len(lines) != 1 {
+		t.Fatalf("expected 1 log line, got %d: %s", len(lines), buf.String())
+	}
+
+	if got, want := lines[0]["email"], hash("jane@example.com"); got != want {
+		t.Errorf("expected hashed email %q, got %v", want, got)
+	}
+
+	if got := lines[0]["user"]; got != "jane" {
+		t.Errorf("expected user jane, got %v", got)
+	}
+}
+
+func BenchmarkInfoFieldsVsInfow(b *testing.B) {
+	l, err := NewLogger(Configuration{Output: io.Discard, PIIMode: PIIModeHash})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.Run("Infow", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			l.Infow("request", "method", "GET", "status", 200, "duration", time.Millisecond)
+		}
+	})
+
+	b.Run("InfoFields", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			l.InfoFields("request", zap.String("method", "GET"), zap.Int("status", 200), zap.Duration("duration", time.Millisecond))
+		}
+	})
+
+	b.Run("InfowPII", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			l.Infow("request", "method", "GET", PII("email", "jane@example.com"))
+		}
+	})
+
+	b.Run("InfoFieldsPII", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			l.InfoFields("request", zap.String("method", "GET"), PIIField("email", "jane@example.com"))
+		}
+	})
+}
+
+func TestTerminalTypedFields(t *testing.T) {
+	tests := []struct {
+		name  string
+		conf  Configuration
+		log   func(l *Logger)
+		level string
+	}{
+		{
+			name:  "DPanicFields",
+			conf:  Configuration{Development: true},
+			log:   func(l *Logger) { l.DPanicFields("terminal", zap.Int("n", 1)) },
+			level: "dpanic",
+		},
+		{
+			name:  "PanicFields",
+			log:   func(l *Logger) { l.PanicFields("terminal", zap.Int("n", 1)) },
+			level: "panic",
+		},
+		{
+			name: "FatalFields",
+			// the hook replaces the exit, so it can be tested
+			conf:  Configuration{ZapOptions: []zap.Option{zap.WithFatalHook(zapcore.WriteThenPanic)}},
+			log:   func(l *Logger) { l.FatalFields("terminal", zap.Int("n", 1)) },
+			level: "fatal",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, buf := newTestLogger(t, tt.conf)
+
+			func() {
+				defer func() {
+					if r := recover(); r != "terminal" {
+						t.Errorf("expected a panic with the message, got %v", r)
+					}
+				}()
+
+				tt.log(l)
+			}()
+
+			lines := buf.lines(t)
+			if len(lines) != 1 {
+				t.Fatalf("expected 1 log line, got %d: %s", len(lines), buf.String())
+			}
+
+			if got := lines[0]["severity"]; got != tt.level {
+				t.Errorf("expected level %q, got %v", tt.level, got)
+			}
+
+			if caller, _ := lines[0]["caller"].(string); !strings.Contains(caller, "typed_test.go:") {
+				t.Errorf("expected the test as caller, got %q", caller)
+			}
+		})
+	}
+}